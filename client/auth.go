@@ -0,0 +1,46 @@
+package client
+
+import "net/http"
+
+// Authenticator adds credentials to requests sent to Kafka Manager. It is
+// called once per attempt, so implementations may return different
+// credentials for a retried request.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// RefreshableAuthenticator is an Authenticator whose credentials can expire
+// before the server says so. When a request is rejected with 401, the client
+// invalidates the credentials and repeats the request once.
+type RefreshableAuthenticator interface {
+	Authenticator
+	Invalidate()
+}
+
+// AccessTokenAuthenticator authenticates through the Data Platform Gateway
+// with an Okta access token.
+type AccessTokenAuthenticator struct {
+	AccessToken string
+}
+
+func (a *AccessTokenAuthenticator) Authenticate(req *http.Request) error {
+	req.Header.Set("Access-Token", a.AccessToken)
+	return nil
+}
+
+// DataPlatformKeyAuthenticator authenticates directly against Kafka Manager
+// with a Data Platform key on behalf of the given Okta groups and supplier.
+type DataPlatformKeyAuthenticator struct {
+	Key        string
+	OktaGroups string
+	Supplier   string
+	UserID     string
+}
+
+func (a *DataPlatformKeyAuthenticator) Authenticate(req *http.Request) error {
+	req.Header.Set("CAI-Data-Platform-Key", a.Key)
+	req.Header.Set("CAI-Data-Platform-Okta-Groups", a.OktaGroups)
+	req.Header.Set("CAI-Data-Platform-Supplier", a.Supplier)
+	req.Header.Set("CAI-Data-Platform-User-Id", a.UserID)
+	return nil
+}
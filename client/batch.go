@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// BatchingClient answers GetTopicInCluster and ListTopicsInCluster from a
+// snapshot of all topics of the cluster, fetched once and shared by
+// concurrent readers. Refreshing a large workspace then costs one paginated
+// listing per cluster instead of one request per topic. The snapshot is kept
+// for the lifetime of the client, which is one Terraform run. Topics missing
+// from the snapshot, and topics written through this client since the
+// snapshot was taken, are read individually.
+type BatchingClient struct {
+	Client
+	snapshots *lookupCache
+
+	mu    sync.Mutex
+	dirty map[int]bool
+}
+
+var _ Client = (*BatchingClient)(nil)
+
+func NewBatchingClient(c Client) *BatchingClient {
+	return &BatchingClient{
+		Client: c,
+		snapshots: &lookupCache{
+			ttl:     -1,
+			entries: make(map[string]*cacheEntry),
+		},
+		dirty: make(map[int]bool),
+	}
+}
+
+func (c *BatchingClient) GetTopicInCluster(ctx context.Context, clusterID int, id int) (*Topic, error) {
+	c.mu.Lock()
+	dirty := c.dirty[id]
+	c.mu.Unlock()
+
+	if !dirty {
+		snapshot, err := c.snapshot(ctx, clusterID)
+		if err != nil {
+			return nil, err
+		}
+		if topic, ok := snapshot[id]; ok {
+			return copyTopic(&topic), nil
+		}
+	}
+
+	return c.Client.GetTopicInCluster(ctx, clusterID, id)
+}
+
+// ListTopicsInCluster answers from the snapshot of the cluster, which does
+// not reflect the topics written through this client since it was taken.
+func (c *BatchingClient) ListTopicsInCluster(ctx context.Context, clusterID int) ([]Topic, error) {
+	snapshot, err := c.snapshot(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	topics := make([]Topic, 0, len(snapshot))
+	for _, t := range snapshot {
+		topics = append(topics, *copyTopic(&t))
+	}
+	sort.Slice(topics, func(i, j int) bool {
+		return topics[i].ID < topics[j].ID
+	})
+	return topics, nil
+}
+
+func (c *BatchingClient) snapshot(ctx context.Context, clusterID int) (map[int]Topic, error) {
+	v, err := c.snapshots.get(ctx, strconv.Itoa(clusterID), func() (interface{}, error) {
+		topics, err := c.Client.ListTopics(ctx, ListTopicsOptions{ClusterID: clusterID})
+		if err != nil {
+			return nil, err
+		}
+		snapshot := make(map[int]Topic, len(topics))
+		for _, t := range topics {
+			snapshot[t.ID] = t
+		}
+		return snapshot, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(map[int]Topic), nil
+}
+
+func (c *BatchingClient) markDirty(id int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dirty[id] = true
+}
+
+func (c *BatchingClient) CreateTopic(ctx context.Context, t *NewTopic) (*Topic, error) {
+	topic, err := c.Client.CreateTopic(ctx, t)
+	if topic != nil {
+		c.markDirty(topic.ID)
+	}
+	return topic, err
+}
+
+func (c *BatchingClient) UpdateTopic(ctx context.Context, t *Topic) error {
+	c.markDirty(t.ID)
+	return c.Client.UpdateTopic(ctx, t)
+}
+
+func (c *BatchingClient) DeleteTopic(ctx context.Context, id int, etag string) error {
+	c.markDirty(id)
+	return c.Client.DeleteTopic(ctx, id, etag)
+}
+
+// copyTopic returns a copy of t that shares no pointers with it.
+func copyTopic(t *Topic) *Topic {
+	topic := *t
+	if t.Cluster != nil {
+		cluster := *t.Cluster
+		topic.Cluster = &cluster
+	}
+	if t.Config != nil {
+		config := *t.Config
+		config.ReplicationFactor = copyInt(t.Config.ReplicationFactor)
+		config.MaxMessageBytes = copyInt(t.Config.MaxMessageBytes)
+		config.RetentionMs = copyInt(t.Config.RetentionMs)
+		config.RetentionBytes = copyInt(t.Config.RetentionBytes)
+		config.CleanupPolicy = copyString(t.Config.CleanupPolicy)
+		config.Settings = copyStringMap(t.Config.Settings)
+		config.Reset = append([]string(nil), t.Config.Reset...)
+		topic.Config = &config
+	}
+	topic.Version = copyInt(t.Version)
+	topic.Description = copyString(t.Description)
+	topic.Owner = copyString(t.Owner)
+	topic.DataClassification = copyString(t.DataClassification)
+	topic.Tags = copyStringMap(t.Tags)
+	return &topic
+}
+
+func copyInt(v *int) *int {
+	if v == nil {
+		return nil
+	}
+	return Int(*v)
+}
+
+func copyString(v *string) *string {
+	if v == nil {
+		return nil
+	}
+	return String(*v)
+}
+
+func copyStringMap(m map[string]*string) map[string]*string {
+	if m == nil {
+		return nil
+	}
+	result := make(map[string]*string, len(m))
+	for key, value := range m {
+		result[key] = copyString(value)
+	}
+	return result
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBatchingClient_ReadsFromClusterSnapshot(t *testing.T) {
+	var listCalls, getCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/topics":
+			atomic.AddInt32(&listCalls, 1)
+			if r.URL.Query().Get("cluster.id") != "1" {
+				t.Errorf("expected listing to be filtered by cluster, got %q", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"items": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}, {"id": 3, "name": "c"}]}`))
+		case "/topics/4":
+			atomic.AddInt32(&getCalls, 1)
+			w.Write([]byte(`{"id": 4, "name": "d"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// The snapshot does not follow the lookup cache TTL.
+	c := NewBatchingClient(NewCachingClient(NewClient(server.URL, nil), 0))
+
+	var wg sync.WaitGroup
+	for id := 1; id <= 4; id++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			topic, err := c.GetTopicInCluster(context.Background(), 1, id)
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+				return
+			}
+			if topic.ID != id {
+				t.Errorf("expected topic %d, got %d", id, topic.ID)
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	if _, err := c.GetTopicInCluster(context.Background(), 1, 2); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	topics, err := c.ListTopicsInCluster(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(topics) != 3 || topics[0].ID != 1 {
+		t.Fatalf("expected the 3 topics of the snapshot, got %#v", topics)
+	}
+
+	if listCalls != 1 || getCalls != 1 {
+		t.Fatalf("expected 1 listing and 1 fallback read, got %d and %d", listCalls, getCalls)
+	}
+}
+
+func TestBatchingClient_ReadsWrittenTopicsIndividually(t *testing.T) {
+	var getCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/topics":
+			w.Write([]byte(`{"items": [{"id": 1, "name": "a", "partitionsCount": 3}]}`))
+		case r.URL.Path == "/topics/1" && r.Method == "PATCH":
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/topics/1":
+			atomic.AddInt32(&getCalls, 1)
+			w.Write([]byte(`{"id": 1, "name": "a", "partitionsCount": 6}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewBatchingClient(NewClient(server.URL, nil))
+
+	if _, err := c.GetTopicInCluster(context.Background(), 1, 1); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := c.UpdateTopic(context.Background(), &Topic{ID: 1, Partitions: 6}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	topic, err := c.GetTopicInCluster(context.Background(), 1, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if topic.Partitions != 6 || getCalls != 1 {
+		t.Fatalf("expected a fresh read after the update, got %d partitions from %d reads", topic.Partitions, getCalls)
+	}
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const DefaultLookupCacheTTL = 5 * time.Minute
+
+// CachingClient answers lookups of clusters, environments and schema
+// registries from a read-through cache. These objects rarely change, while
+// a single plan may resolve the same one hundreds of times. Concurrent
+// lookups of the same key share one request, and every caller gets its own
+// copy of the result. Everything else is passed through to the wrapped
+// Client.
+type CachingClient struct {
+	Client
+	cache *lookupCache
+}
+
+var _ Client = (*CachingClient)(nil)
+
+func NewCachingClient(c Client, ttl time.Duration) *CachingClient {
+	return &CachingClient{
+		Client: c,
+		cache: &lookupCache{
+			ttl:     ttl,
+			entries: make(map[string]*cacheEntry),
+		},
+	}
+}
+
+type cacheEntry struct {
+	done    chan struct{}
+	value   interface{}
+	err     error
+	expires time.Time
+}
+
+type lookupCache struct {
+	// ttl is how long a value stays valid. A negative ttl keeps values for
+	// the lifetime of the cache.
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// get returns the cached value for key, calling fetch if there is none or
+// it has expired. Failed fetches are not cached.
+func (c *lookupCache) get(ctx context.Context, key string, fetch func() (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok {
+		select {
+		case <-entry.done:
+			if c.ttl >= 0 && time.Now().After(entry.expires) {
+				ok = false
+			}
+		default:
+			// Another caller is fetching the value.
+		}
+	}
+	if ok {
+		c.mu.Unlock()
+		select {
+		case <-entry.done:
+			return entry.value, entry.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	entry = &cacheEntry{done: make(chan struct{})}
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	entry.value, entry.err = fetch()
+	entry.expires = time.Now().Add(c.ttl)
+
+	if entry.err != nil {
+		c.mu.Lock()
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+	}
+	close(entry.done)
+
+	return entry.value, entry.err
+}
+
+func (c *CachingClient) getEnvironment(ctx context.Context, key string, fetch func() (*Environment, error)) (*Environment, error) {
+	v, err := c.cache.get(ctx, "environment/"+key, func() (interface{}, error) { return fetch() })
+	if err != nil {
+		return nil, err
+	}
+	environment := *v.(*Environment)
+	return &environment, nil
+}
+
+func (c *CachingClient) GetEnvironments(ctx context.Context) ([]Environment, error) {
+	v, err := c.cache.get(ctx, "environments", func() (interface{}, error) { return c.Client.GetEnvironments(ctx) })
+	if err != nil {
+		return nil, err
+	}
+	return append([]Environment(nil), v.([]Environment)...), nil
+}
+
+func (c *CachingClient) GetEnvironment(ctx context.Context, id int) (*Environment, error) {
+	return c.getEnvironment(ctx, fmt.Sprintf("id/%d", id), func() (*Environment, error) { return c.Client.GetEnvironment(ctx, id) })
+}
+
+func (c *CachingClient) GetEnvironmentByName(ctx context.Context, name string) (*Environment, error) {
+	return c.getEnvironment(ctx, "name/"+name, func() (*Environment, error) { return c.Client.GetEnvironmentByName(ctx, name) })
+}
+
+func (c *CachingClient) GetEnvironmentByConfluentID(ctx context.Context, confluentID string) (*Environment, error) {
+	return c.getEnvironment(ctx, "confluentId/"+confluentID, func() (*Environment, error) { return c.Client.GetEnvironmentByConfluentID(ctx, confluentID) })
+}
+
+func (c *CachingClient) getSchemaRegistry(ctx context.Context, key string, fetch func() (*SchemaRegistry, error)) (*SchemaRegistry, error) {
+	v, err := c.cache.get(ctx, "schemaRegistry/"+key, func() (interface{}, error) { return fetch() })
+	if err != nil {
+		return nil, err
+	}
+	schemaRegistry := *v.(*SchemaRegistry)
+	return &schemaRegistry, nil
+}
+
+func (c *CachingClient) GetSchemaRegistries(ctx context.Context) ([]SchemaRegistry, error) {
+	v, err := c.cache.get(ctx, "schemaRegistries", func() (interface{}, error) { return c.Client.GetSchemaRegistries(ctx) })
+	if err != nil {
+		return nil, err
+	}
+	return append([]SchemaRegistry(nil), v.([]SchemaRegistry)...), nil
+}
+
+func (c *CachingClient) GetSchemaRegistry(ctx context.Context, id int) (*SchemaRegistry, error) {
+	return c.getSchemaRegistry(ctx, fmt.Sprintf("id/%d", id), func() (*SchemaRegistry, error) { return c.Client.GetSchemaRegistry(ctx, id) })
+}
+
+func (c *CachingClient) GetSchemaRegistryByConfluentID(ctx context.Context, confluentID string) (*SchemaRegistry, error) {
+	return c.getSchemaRegistry(ctx, "confluentId/"+confluentID, func() (*SchemaRegistry, error) { return c.Client.GetSchemaRegistryByConfluentID(ctx, confluentID) })
+}
+
+func (c *CachingClient) getCluster(ctx context.Context, key string, fetch func() (*Cluster, error)) (*Cluster, error) {
+	v, err := c.cache.get(ctx, "cluster/"+key, func() (interface{}, error) { return fetch() })
+	if err != nil {
+		return nil, err
+	}
+	cluster := *v.(*Cluster)
+	return &cluster, nil
+}
+
+func (c *CachingClient) GetClusters(ctx context.Context) ([]Cluster, error) {
+	v, err := c.cache.get(ctx, "clusters", func() (interface{}, error) { return c.Client.GetClusters(ctx) })
+	if err != nil {
+		return nil, err
+	}
+	return append([]Cluster(nil), v.([]Cluster)...), nil
+}
+
+func (c *CachingClient) GetCluster(ctx context.Context, id int) (*Cluster, error) {
+	return c.getCluster(ctx, fmt.Sprintf("id/%d", id), func() (*Cluster, error) { return c.Client.GetCluster(ctx, id) })
+}
+
+func (c *CachingClient) GetClusterByName(ctx context.Context, name string) (*Cluster, error) {
+	return c.getCluster(ctx, "name/"+name, func() (*Cluster, error) { return c.Client.GetClusterByName(ctx, name) })
+}
+
+func (c *CachingClient) GetClusterByNameAndEnvironmentID(ctx context.Context, name string, environmentID int) (*Cluster, error) {
+	return c.getCluster(ctx, fmt.Sprintf("environment/%d/name/%s", environmentID, name), func() (*Cluster, error) { return c.Client.GetClusterByNameAndEnvironmentID(ctx, name, environmentID) })
+}
+
+func (c *CachingClient) GetClusterByConfluentID(ctx context.Context, confluentID string) (*Cluster, error) {
+	return c.getCluster(ctx, "confluentId/"+confluentID, func() (*Cluster, error) { return c.Client.GetClusterByConfluentID(ctx, confluentID) })
+}
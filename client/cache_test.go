@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCachingClient_DeduplicatesLookups(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{"items": [{"id": 1, "name": "dev"}]}`))
+	}))
+	defer server.Close()
+
+	c := NewCachingClient(NewClient(server.URL, nil), time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cluster, err := c.GetClusterByName(context.Background(), "dev")
+			if err != nil {
+				t.Errorf("unexpected error: %s", err)
+				return
+			}
+			cluster.Name = "modified"
+		}()
+	}
+	wg.Wait()
+
+	cluster, err := c.GetClusterByName(context.Background(), "dev")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cluster.Name != "dev" {
+		t.Fatalf("cached cluster was modified by a caller: %q", cluster.Name)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestCachingClient_ExpiresEntriesAndSkipsErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"id": 1, "name": "dev"}`))
+	}))
+	defer server.Close()
+
+	c := NewCachingClient(NewClient(server.URL, nil), 0)
+
+	if _, err := c.GetEnvironment(context.Background(), 1); !IsForbidden(err) {
+		t.Fatalf("expected a forbidden error, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetEnvironment(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls with a zero TTL, got %d", calls)
+	}
+}
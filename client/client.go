@@ -1,116 +1,163 @@
 package client
 
 import (
-	"fmt"
+	"context"
 	"io/ioutil"
 	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 type Client interface {
-	getURL() string
-	getHTTPClient() *http.Client
-	doRequest(req *http.Request) ([]byte, error)
-
-	GetEnvironments() ([]Environment, error)
-	GetEnvironment(id int) (*Environment, error)
-	GetEnvironmentByName(name string) (*Environment, error)
-	GetEnvironmentByConfluentID(confluentID string) (*Environment, error)
-	GetSchemaRegistries() ([]SchemaRegistry, error)
-	GetSchemaRegistry(id int) (*SchemaRegistry, error)
-	GetSchemaRegistryByConfluentID(confluentID string) (*SchemaRegistry, error)
-	GetClusters() ([]Cluster, error)
-	GetCluster(id int) (*Cluster, error)
-	GetClusterByName(name string) (*Cluster, error)
-	GetClusterByConfluentID(confluentID string) (*Cluster, error)
-	GetTopics() ([]Topic, error)
-	GetTopic(id int) (*Topic, error)
-	GetTopicByNameAndClusterID(name string, clusterID int) (*Topic, error)
-	CreateTopic(t *NewTopic) (*Topic, error)
-	UpdateTopic(t *Topic) error
-	DeleteTopic(id int) error
+	GetEnvironments(ctx context.Context) ([]Environment, error)
+	IterateEnvironments(ctx context.Context) *EnvironmentIterator
+	GetEnvironment(ctx context.Context, id int) (*Environment, error)
+	GetEnvironmentByName(ctx context.Context, name string) (*Environment, error)
+	GetEnvironmentByConfluentID(ctx context.Context, confluentID string) (*Environment, error)
+	GetSchemaRegistries(ctx context.Context) ([]SchemaRegistry, error)
+	IterateSchemaRegistries(ctx context.Context) *SchemaRegistryIterator
+	GetSchemaRegistry(ctx context.Context, id int) (*SchemaRegistry, error)
+	GetSchemaRegistryByConfluentID(ctx context.Context, confluentID string) (*SchemaRegistry, error)
+	GetClusters(ctx context.Context) ([]Cluster, error)
+	IterateClusters(ctx context.Context) *ClusterIterator
+	GetCluster(ctx context.Context, id int) (*Cluster, error)
+	GetClusterByName(ctx context.Context, name string) (*Cluster, error)
+	GetClusterByNameAndEnvironmentID(ctx context.Context, name string, environmentID int) (*Cluster, error)
+	GetClusterByConfluentID(ctx context.Context, confluentID string) (*Cluster, error)
+	GetTopics(ctx context.Context) ([]Topic, error)
+	IterateTopics(ctx context.Context) *TopicIterator
+	ListTopics(ctx context.Context, opts ListTopicsOptions) ([]Topic, error)
+	GetTopic(ctx context.Context, id int) (*Topic, error)
+	GetTopicInCluster(ctx context.Context, clusterID int, id int) (*Topic, error)
+	ListTopicsInCluster(ctx context.Context, clusterID int) ([]Topic, error)
+	GetTopicByNameAndClusterID(ctx context.Context, name string, clusterID int) (*Topic, error)
+	CreateTopic(ctx context.Context, t *NewTopic) (*Topic, error)
+	UpdateTopic(ctx context.Context, t *Topic) error
+	DeleteTopic(ctx context.Context, id int, etag string) error
 }
 
-func doRequest(c Client, req *http.Request) ([]byte, error) {
-
-	res, err := c.getHTTPClient().Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-	body, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	if res.StatusCode == http.StatusOK || res.StatusCode == http.StatusCreated || res.StatusCode == http.StatusNoContent {
-		return body, err
-	} else {
-		return nil, fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
-	}
+// APIClient talks to the Kafka Manager REST API. Credentials are added to
+// every request by its Authenticator.
+type APIClient struct {
+	HttpClient    *http.Client
+	RetryPolicy   RetryPolicy
+	URL           string
+	Authenticator Authenticator
+
+	// RateLimiter and ConcurrencyLimiter throttle every request made
+	// through the client. Either may be nil to disable it.
+	RateLimiter        *RateLimiter
+	ConcurrencyLimiter *ConcurrencyLimiter
 }
 
-type PrivateClient struct {
-	HttpClient *http.Client
-	URL        string
-	Key        string
-	OktaGroups string
-	Supplier   string
-	UserID     string
-}
+var _ Client = (*APIClient)(nil)
 
-func NewPrivateClient(URL string, Key string, OktaGroups string, Supplier string, UserID string) *PrivateClient {
-	return &PrivateClient{
-		HttpClient: http.DefaultClient,
-		URL:        URL,
-		Key:        Key,
-		OktaGroups: OktaGroups,
-		Supplier:   Supplier,
-		UserID:     UserID,
+func NewClient(URL string, authenticator Authenticator) *APIClient {
+	return &APIClient{
+		HttpClient:    &http.Client{Transport: NewLoggingTransport(http.DefaultTransport)},
+		RetryPolicy:   DefaultRetryPolicy(),
+		URL:           URL,
+		Authenticator: authenticator,
 	}
 }
 
-func (c *PrivateClient) getURL() string {
-	return c.URL
+func (c *APIClient) doRequest(req *http.Request) ([]byte, error) {
+	body, _, err := c.doRequestWithHeaders(req)
+	return body, err
 }
 
-func (c *PrivateClient) getHTTPClient() *http.Client {
-	return c.HttpClient
-}
-
-func (c *PrivateClient) doRequest(req *http.Request) ([]byte, error) {
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("CAI-Data-Platform-Key", c.Key)
-	req.Header.Set("CAI-Data-Platform-Okta-Groups", c.OktaGroups)
-	req.Header.Set("CAI-Data-Platform-Supplier", c.Supplier)
-	req.Header.Set("CAI-Data-Platform-User-Id", c.UserID)
-
-	return doRequest(c, req)
-}
-
-type PublicClient struct {
-	HttpClient  *http.Client
-	URL         string
-	AccessToken string
-}
-
-func NewPublicClient(URL string, AccessToken string) *PublicClient {
-	return &PublicClient{
-		HttpClient:  http.DefaultClient,
-		URL:         URL,
-		AccessToken: AccessToken,
+// doRequestWithHeaders sends req, retrying it according to the retry
+// policy, and returns the body and headers of the successful response.
+func (c *APIClient) doRequestWithHeaders(req *http.Request) ([]byte, http.Header, error) {
+	policy := c.RetryPolicy
+	reauthenticated := false
+
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 || reauthenticated {
+			r = req.Clone(req.Context())
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, nil, err
+				}
+				r.Body = body
+			}
+		}
+
+		r.Header.Set("Content-Type", "application/json")
+		if c.Authenticator != nil {
+			if err := c.Authenticator.Authenticate(r); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		res, body, err := c.send(r)
+		if err == nil && (res.StatusCode == http.StatusOK || res.StatusCode == http.StatusCreated || res.StatusCode == http.StatusAccepted || res.StatusCode == http.StatusNoContent) {
+			return body, res.Header, nil
+		}
+		if err != nil && req.Context().Err() != nil {
+			return nil, nil, req.Context().Err()
+		}
+
+		if err == nil && res.StatusCode == http.StatusUnauthorized && !reauthenticated {
+			if refreshable, ok := c.Authenticator.(RefreshableAuthenticator); ok {
+				tflog.Debug(req.Context(), "Kafka Manager API request unauthorized, refreshing credentials", "method", req.Method, "url", req.URL.String())
+				refreshable.Invalidate()
+				reauthenticated = true
+				attempt--
+				continue
+			}
+		}
+
+		if attempt >= policy.MaxRetries || !policy.shouldRetry(req, res, err) {
+			if err != nil {
+				return nil, nil, err
+			}
+			return nil, nil, newAPIError(res.StatusCode, body)
+		}
+
+		wait := policy.backoff(attempt+1, res)
+		if err != nil {
+			tflog.Debug(req.Context(), "Retrying Kafka Manager API request", "method", req.Method, "url", req.URL.String(), "error", err.Error(), "wait", wait.String(), "attempt", attempt+1, "max_retries", policy.MaxRetries)
+		} else {
+			tflog.Debug(req.Context(), "Retrying Kafka Manager API request", "method", req.Method, "url", req.URL.String(), "status", res.StatusCode, "wait", wait.String(), "attempt", attempt+1, "max_retries", policy.MaxRetries)
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, nil, req.Context().Err()
+		case <-time.After(wait):
+		}
 	}
 }
 
-func (c *PublicClient) getURL() string {
-	return c.URL
-}
+// send performs a single attempt of req once the rate and concurrency
+// limits allow it, and reads the whole response body.
+func (c *APIClient) send(req *http.Request) (*http.Response, []byte, error) {
+	if c.RateLimiter != nil {
+		if err := c.RateLimiter.Wait(req.Context()); err != nil {
+			return nil, nil, err
+		}
+	}
+	if c.ConcurrencyLimiter != nil {
+		if err := c.ConcurrencyLimiter.Acquire(req.Context()); err != nil {
+			return nil, nil, err
+		}
+		defer c.ConcurrencyLimiter.Release()
+	}
 
-func (c *PublicClient) getHTTPClient() *http.Client {
-	return c.HttpClient
-}
+	res, err := c.HttpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
 
-func (c *PublicClient) doRequest(req *http.Request) ([]byte, error) {
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Access-Token", c.AccessToken)
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	return doRequest(c, req)
+	return res, body, nil
 }
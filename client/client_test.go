@@ -0,0 +1,406 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		MinWait:    time.Millisecond,
+		MaxWait:    10 * time.Millisecond,
+	}
+}
+
+func TestDoRequest_RetriesGetOnServiceUnavailable(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id": 1, "name": "dev"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, &AccessTokenAuthenticator{AccessToken: "token"})
+	c.RetryPolicy = testRetryPolicy()
+
+	environment, err := c.GetEnvironment(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if environment.Name != "dev" {
+		t.Fatalf("expected environment dev, got %q", environment.Name)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestDoRequest_RetriesPostWithBody(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength == 0 {
+			t.Error("request was sent without a body")
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 7, "name": "orders"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, &AccessTokenAuthenticator{AccessToken: "token"})
+	c.RetryPolicy = testRetryPolicy()
+
+	topic, err := c.CreateTopic(context.Background(), &NewTopic{ClusterID: 1, Name: "orders"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if topic.ID != 7 {
+		t.Fatalf("expected topic 7, got %d", topic.ID)
+	}
+}
+
+func TestDoRequest_DoesNotRetryPostOnServerError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, &AccessTokenAuthenticator{AccessToken: "token"})
+	c.RetryPolicy = testRetryPolicy()
+
+	if _, err := c.CreateTopic(context.Background(), &NewTopic{ClusterID: 1, Name: "orders"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestDoRequest_DoesNotRetryConditionalRequests(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, &AccessTokenAuthenticator{AccessToken: "token"})
+	c.RetryPolicy = testRetryPolicy()
+
+	if err := c.DeleteTopic(context.Background(), 1, `"3"`); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+
+	if err := c.DeleteTopic(context.Background(), 1, ""); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls == 2 {
+		t.Fatal("expected an unconditional delete to be retried")
+	}
+}
+
+func TestDoRequest_StopsOnContextCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, &AccessTokenAuthenticator{AccessToken: "token"})
+	c.RetryPolicy = RetryPolicy{MaxRetries: 10, MinWait: time.Hour, MaxWait: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := c.GetClusters(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("retry backoff did not honor context cancellation")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if wait, ok := parseRetryAfter("5"); !ok || wait != 5*time.Second {
+		t.Fatalf("expected 5s, got %s (%t)", wait, ok)
+	}
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Fatal("expected an invalid Retry-After value to be ignored")
+	}
+	if wait, ok := parseRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)); !ok || wait != 0 {
+		t.Fatalf("expected a past date to yield 0, got %s (%t)", wait, ok)
+	}
+}
+
+func TestDoRequest_ReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code": "topics.does_not_exist", "message": "Topic does not exist"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, &AccessTokenAuthenticator{AccessToken: "token"})
+	c.RetryPolicy = testRetryPolicy()
+
+	_, err := c.GetTopic(context.Background(), 1)
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Code != "topics.does_not_exist" {
+		t.Fatalf("unexpected error fields: %#v", apiErr)
+	}
+	if !IsNotFound(err) {
+		t.Fatal("expected IsNotFound to be true")
+	}
+	if IsForbidden(err) || IsConflict(err) {
+		t.Fatal("expected IsForbidden and IsConflict to be false")
+	}
+}
+
+func TestIsNotFound_Lookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"items": []}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, &AccessTokenAuthenticator{AccessToken: "token"})
+
+	_, err := c.GetClusterByName(context.Background(), "missing")
+	if !IsNotFound(err) {
+		t.Fatalf("expected IsNotFound to be true, got %v", err)
+	}
+}
+
+func TestGetClusterByNameAndEnvironmentID_SendsFilters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") != "shared" || r.URL.Query().Get("environment.id") != "7" {
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"items": [{"id": 3, "name": "shared"}]}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, &AccessTokenAuthenticator{AccessToken: "token"})
+
+	cluster, err := c.GetClusterByNameAndEnvironmentID(context.Background(), "shared", 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cluster.ID != 3 {
+		t.Fatalf("expected cluster 3, got %d", cluster.ID)
+	}
+}
+
+func TestIterateTopics_FollowsPages(t *testing.T) {
+	pages := []string{
+		`{"items": [{"id": 1}, {"id": 2}], "page": 0, "totalPages": 3}`,
+		`{"items": [{"id": 3}, {"id": 4}], "page": 1, "totalPages": 3}`,
+		`{"items": [{"id": 5}], "page": 2, "totalPages": 3}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil || page >= len(pages) {
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(pages[page]))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, &AccessTokenAuthenticator{AccessToken: "token"})
+
+	topics, err := c.GetTopics(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(topics) != 5 || topics[4].ID != 5 {
+		t.Fatalf("expected 5 topics across all pages, got %#v", topics)
+	}
+}
+
+func TestIterateClusters_StopsOnShortPage(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{"items": [{"id": 1}, {"id": 2}]}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, &AccessTokenAuthenticator{AccessToken: "token"})
+
+	it := c.IterateClusters(context.Background())
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if count != 2 || calls != 1 {
+		t.Fatalf("expected 2 clusters from 1 call, got %d from %d", count, calls)
+	}
+}
+
+func TestIterateTopics_StopsOnRepeatedPage(t *testing.T) {
+	items := make([]string, defaultPageSize)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"id": %d}`, i+1)
+	}
+	body := `{"items": [` + strings.Join(items, ",") + `]}`
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, &AccessTokenAuthenticator{AccessToken: "token"})
+
+	topics, err := c.GetTopics(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(topics) != defaultPageSize || calls != 2 {
+		t.Fatalf("expected %d topics from 2 calls, got %d from %d", defaultPageSize, len(topics), calls)
+	}
+}
+
+func TestListTopics_SendsFilters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		expected := map[string]string{
+			"cluster.id":                   "10",
+			"cluster.environment.id":       "3",
+			"name.startsWith":              "orders_",
+			"cluster.environment.supplier": "dev-1",
+			"owner":                        "data-platform",
+			"dataClassification":           "confidential",
+			"tags.domain":                  "sales",
+			"sort":                         "name,desc",
+		}
+		for key, value := range expected {
+			if got := q.Get(key); got != value {
+				t.Errorf("expected %s=%q, got %q", key, value, got)
+			}
+		}
+		w.Write([]byte(`{"items": []}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, &AccessTokenAuthenticator{AccessToken: "token"})
+
+	_, err := c.ListTopics(context.Background(), ListTopicsOptions{
+		ClusterID:          10,
+		EnvironmentID:      3,
+		NamePrefix:         "orders_",
+		Supplier:           "dev-1",
+		Owner:              "data-platform",
+		DataClassification: DataClassificationConfidential,
+		Tags:               map[string]string{"domain": "sales"},
+		SortBy:             "name",
+		SortDescending:     true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestAuthenticators_SetHeaders(t *testing.T) {
+	cases := map[string]struct {
+		authenticator Authenticator
+		headers       map[string]string
+	}{
+		"access token": {
+			authenticator: &AccessTokenAuthenticator{AccessToken: "token"},
+			headers:       map[string]string{"Access-Token": "token"},
+		},
+		"data platform key": {
+			authenticator: &DataPlatformKeyAuthenticator{Key: "key", OktaGroups: "GROUP", Supplier: "dev-1", UserID: "USER"},
+			headers: map[string]string{
+				"CAI-Data-Platform-Key":         "key",
+				"CAI-Data-Platform-Okta-Groups": "GROUP",
+				"CAI-Data-Platform-Supplier":    "dev-1",
+				"CAI-Data-Platform-User-Id":     "USER",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for key, value := range tc.headers {
+					if got := r.Header.Get(key); got != value {
+						t.Errorf("expected header %s=%q, got %q", key, value, got)
+					}
+				}
+				w.Write([]byte(`{"id": 1}`))
+			}))
+			defer server.Close()
+
+			c := NewClient(server.URL, tc.authenticator)
+			if _, err := c.GetCluster(context.Background(), 1); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestUpdateTopic_SendsIfMatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("ETag", `"3"`)
+			w.Write([]byte(`{"id": 1, "name": "orders"}`))
+		case http.MethodPatch, http.MethodDelete:
+			if got := r.Header.Get("If-Match"); got != `"3"` {
+				t.Errorf("expected If-Match %q, got %q", `"3"`, got)
+			}
+			w.WriteHeader(http.StatusPreconditionFailed)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, &AccessTokenAuthenticator{AccessToken: "token"})
+	c.RetryPolicy = testRetryPolicy()
+
+	topic, err := c.GetTopic(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if topic.ETag != `"3"` {
+		t.Fatalf("expected entity tag %q, got %q", `"3"`, topic.ETag)
+	}
+
+	err = c.UpdateTopic(context.Background(), topic)
+	if !IsPreconditionFailed(err) {
+		t.Fatalf("expected IsPreconditionFailed to be true, got %v", err)
+	}
+
+	err = c.DeleteTopic(context.Background(), 1, topic.ETag)
+	if !IsPreconditionFailed(err) {
+		t.Fatalf("expected IsPreconditionFailed to be true, got %v", err)
+	}
+}
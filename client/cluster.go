@@ -1,9 +1,13 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
 type Cluster struct {
@@ -21,47 +25,138 @@ type Cluster struct {
 	RestProxyUrl                 string      `json:"restProxyUrl"`
 	BootstrapServers             string      `json:"bootstrapServers"`
 	DefaultS3BucketName          string      `json:"defaultS3BucketName"`
+
+	// Limits as reported by Kafka Manager. Use KnownLimits, which falls
+	// back to the published limits of the cluster type.
+	Limits ClusterLimits `json:"limits"`
+}
+
+// Confluent Cloud cluster types. Basic and Standard clusters run on shared
+// infrastructure.
+const (
+	ClusterTypeBasic     = "Basic"
+	ClusterTypeStandard  = "Standard"
+	ClusterTypeDedicated = "Dedicated"
+)
+
+// ClusterLimits are the limits of a cluster that creating or changing a
+// topic can run into. Zero means that no limit is known.
+type ClusterLimits struct {
+	// MaxPartitions caps the partitions of all topics in the cluster
+	// together, not those of a single topic.
+	MaxPartitions   int `json:"maxPartitions"`
+	MaxMessageBytes int `json:"maxMessageBytes"`
+}
+
+// DefaultClusterLimits are the published limits of Confluent Cloud cluster
+// types. The partition limit of dedicated clusters depends on their size,
+// so only Kafka Manager knows it.
+var DefaultClusterLimits = map[string]ClusterLimits{
+	ClusterTypeBasic: {
+		MaxPartitions:   4096,
+		MaxMessageBytes: 8 * 1024 * 1024,
+	},
+	ClusterTypeStandard: {
+		MaxPartitions:   4096,
+		MaxMessageBytes: 8 * 1024 * 1024,
+	},
+	ClusterTypeDedicated: {
+		MaxMessageBytes: 20 * 1024 * 1024,
+	},
+}
+
+// KnownLimits returns the limits reported by Kafka Manager, completed with
+// the default limits of the cluster type.
+func (c *Cluster) KnownLimits() ClusterLimits {
+	limits := c.Limits
+	for clusterType, defaults := range DefaultClusterLimits {
+		if !strings.EqualFold(c.ClusterType, clusterType) {
+			continue
+		}
+		if limits.MaxPartitions == 0 {
+			limits.MaxPartitions = defaults.MaxPartitions
+		}
+		if limits.MaxMessageBytes == 0 {
+			limits.MaxMessageBytes = defaults.MaxMessageBytes
+		}
+	}
+	return limits
+}
+
+// CanReassignReplicas reports whether Kafka Manager can change the
+// replication factor of existing topics in the cluster. Only dedicated
+// clusters allow partition reassignment, on the others the replication
+// factor is fixed when a topic is created.
+func (c *Cluster) CanReassignReplicas() bool {
+	return strings.EqualFold(c.ClusterType, ClusterTypeDedicated)
 }
 
 type Clusters struct {
 	Items []Cluster `json:"items"`
 }
 
-func getClusters(c Client) ([]Cluster, error) {
-	url := fmt.Sprintf("%s/clusters", c.getURL())
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
+// ClusterIterator streams clusters from Kafka Manager one page at a time.
+type ClusterIterator struct {
+	pager   *pager
+	items   []Cluster
+	current Cluster
+	err     error
+}
 
-	res, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
+// Next advances the iterator and reports whether another item is available.
+func (it *ClusterIterator) Next() bool {
+	for len(it.items) == 0 {
+		if it.err != nil {
+			return false
+		}
+		var items []Cluster
+		ok, err := it.pager.next(&items)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if !ok {
+			return false
+		}
+		it.items = items
 	}
 
-	var clusters Clusters
+	it.current = it.items[0]
+	it.items = it.items[1:]
+	return true
+}
 
-	err = json.Unmarshal(res, &clusters)
-	if err != nil {
-		return nil, err
-	}
+func (it *ClusterIterator) Cluster() Cluster {
+	return it.current
+}
 
-	return clusters.Items, nil
+func (it *ClusterIterator) Err() error {
+	return it.err
 }
 
-func (c *PrivateClient) GetClusters() ([]Cluster, error) {
-	return getClusters(c)
+func (c *APIClient) IterateClusters(ctx context.Context) *ClusterIterator {
+	return &ClusterIterator{pager: c.newPager(ctx, "/clusters", nil)}
 }
 
-func (c *PublicClient) GetClusters() ([]Cluster, error) {
-	return getClusters(c)
+func (c *APIClient) GetClusters(ctx context.Context) ([]Cluster, error) {
+	clusters := make([]Cluster, 0)
+
+	it := c.IterateClusters(ctx)
+	for it.Next() {
+		clusters = append(clusters, it.Cluster())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return clusters, nil
 }
 
-func getCluster(c Client, id int) (*Cluster, error) {
+func (c *APIClient) GetCluster(ctx context.Context, id int) (*Cluster, error) {
 	var cluster Cluster
 
-	url := fmt.Sprintf("%s/clusters/%d", c.getURL(), id)
-	req, err := http.NewRequest("GET", url, nil)
+	url := fmt.Sprintf("%s/clusters/%d", c.URL, id)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -79,23 +174,20 @@ func getCluster(c Client, id int) (*Cluster, error) {
 	return &cluster, nil
 }
 
-func (c *PrivateClient) GetCluster(id int) (*Cluster, error) {
-	return getCluster(c, id)
-}
-
-func (c *PublicClient) GetCluster(id int) (*Cluster, error) {
-	return getCluster(c, id)
+func (c *APIClient) getClusterBy(ctx context.Context, paramKey string, paramValue string) (*Cluster, error) {
+	q := url.Values{}
+	q.Add(paramKey, paramValue)
+	return c.findCluster(ctx, q, fmt.Sprintf("%s %s", paramKey, paramValue))
 }
 
-func getClusterBy(c Client, paramKey string, paramValue string) (*Cluster, error) {
-	url := fmt.Sprintf("%s/clusters", c.getURL())
-	req, err := http.NewRequest("GET", url, nil)
+// findCluster returns the first cluster matching the query. description
+// names the query in errors.
+func (c *APIClient) findCluster(ctx context.Context, q url.Values, description string) (*Cluster, error) {
+	url := fmt.Sprintf("%s/clusters", c.URL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
-
-	q := req.URL.Query()
-	q.Add(paramKey, paramValue)
 	req.URL.RawQuery = q.Encode()
 
 	res, err := c.doRequest(req)
@@ -111,24 +203,25 @@ func getClusterBy(c Client, paramKey string, paramValue string) (*Cluster, error
 	}
 
 	if len(clusters.Items) == 0 {
-		return nil, fmt.Errorf("Cluster with %s %s not found", paramKey, paramValue)
+		return nil, fmt.Errorf("Cluster with %s %w", description, ErrNotFound)
 	} else {
 		return &clusters.Items[0], nil
 	}
 }
 
-func (c *PrivateClient) GetClusterByName(name string) (*Cluster, error) {
-	return getClusterBy(c, "name", name)
-}
-
-func (c *PublicClient) GetClusterByName(name string) (*Cluster, error) {
-	return getClusterBy(c, "name", name)
+func (c *APIClient) GetClusterByName(ctx context.Context, name string) (*Cluster, error) {
+	return c.getClusterBy(ctx, "name", name)
 }
 
-func (c *PrivateClient) GetClusterByConfluentID(confluentID string) (*Cluster, error) {
-	return getClusterBy(c, "confluentId", confluentID)
+// GetClusterByNameAndEnvironmentID looks a cluster up by name within one
+// environment, for when several environments have a cluster of that name.
+func (c *APIClient) GetClusterByNameAndEnvironmentID(ctx context.Context, name string, environmentID int) (*Cluster, error) {
+	q := url.Values{}
+	q.Add("name", name)
+	q.Add("environment.id", strconv.Itoa(environmentID))
+	return c.findCluster(ctx, q, fmt.Sprintf("name %s and environment ID %d", name, environmentID))
 }
 
-func (c *PublicClient) GetClusterByConfluentID(confluentID string) (*Cluster, error) {
-	return getClusterBy(c, "confluentId", confluentID)
+func (c *APIClient) GetClusterByConfluentID(ctx context.Context, confluentID string) (*Cluster, error) {
+	return c.getClusterBy(ctx, "confluentId", confluentID)
 }
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCluster_KnownLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want ClusterLimits
+	}{
+		{
+			name: "built-in",
+			json: `{"clusterType": "standard"}`,
+			want: ClusterLimits{MaxPartitions: 4096, MaxMessageBytes: 8388608},
+		},
+		{
+			name: "from server",
+			json: `{"clusterType": "Dedicated", "limits": {"maxPartitions": 9000}}`,
+			want: ClusterLimits{MaxPartitions: 9000, MaxMessageBytes: 20971520},
+		},
+		{
+			name: "unknown type",
+			json: `{"clusterType": "Enterprise"}`,
+			want: ClusterLimits{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cluster Cluster
+			if err := json.Unmarshal([]byte(tt.json), &cluster); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got := cluster.KnownLimits(); got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
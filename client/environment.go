@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -17,41 +18,68 @@ type Environments struct {
 	Items []Environment `json:"items"`
 }
 
-func getEnvironments(c Client) ([]Environment, error) {
-	url := fmt.Sprintf("%s/environments", c.getURL())
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
+// EnvironmentIterator streams environments from Kafka Manager one page at a time.
+type EnvironmentIterator struct {
+	pager   *pager
+	items   []Environment
+	current Environment
+	err     error
+}
 
-	res, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
+// Next advances the iterator and reports whether another item is available.
+func (it *EnvironmentIterator) Next() bool {
+	for len(it.items) == 0 {
+		if it.err != nil {
+			return false
+		}
+		var items []Environment
+		ok, err := it.pager.next(&items)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if !ok {
+			return false
+		}
+		it.items = items
 	}
 
-	var environments Environments
+	it.current = it.items[0]
+	it.items = it.items[1:]
+	return true
+}
 
-	err = json.Unmarshal(res, &environments)
-	if err != nil {
-		return nil, err
-	}
+func (it *EnvironmentIterator) Environment() Environment {
+	return it.current
+}
 
-	return environments.Items, nil
+func (it *EnvironmentIterator) Err() error {
+	return it.err
 }
 
-func (c *PrivateClient) GetEnvironments() ([]Environment, error) {
-	return getEnvironments(c)
+func (c *APIClient) IterateEnvironments(ctx context.Context) *EnvironmentIterator {
+	return &EnvironmentIterator{pager: c.newPager(ctx, "/environments", nil)}
 }
 
-func (c *PublicClient) GetEnvironments() ([]Environment, error) {
-	return getEnvironments(c)
+func (c *APIClient) GetEnvironments(ctx context.Context) ([]Environment, error) {
+	environments := make([]Environment, 0)
+
+	it := c.IterateEnvironments(ctx)
+	for it.Next() {
+		environments = append(environments, it.Environment())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return environments, nil
 }
 
-func getEnvironment(c Client, id int) (*Environment, error) {
+func (c *APIClient) GetEnvironment(ctx context.Context, id int) (*Environment, error) {
 	var environment Environment
 
-	url := fmt.Sprintf("%s/environments/%d", c.getURL(), id)
-	req, err := http.NewRequest("GET", url, nil)
+	url := fmt.Sprintf("%s/environments/%d", c.URL, id)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -69,17 +97,9 @@ func getEnvironment(c Client, id int) (*Environment, error) {
 	return &environment, nil
 }
 
-func (c *PrivateClient) GetEnvironment(id int) (*Environment, error) {
-	return getEnvironment(c, id)
-}
-
-func (c *PublicClient) GetEnvironment(id int) (*Environment, error) {
-	return getEnvironment(c, id)
-}
-
-func getEnvironmentBy(c Client, paramKey string, paramValue string) (*Environment, error) {
-	url := fmt.Sprintf("%s/environments", c.getURL())
-	req, err := http.NewRequest("GET", url, nil)
+func (c *APIClient) getEnvironmentBy(ctx context.Context, paramKey string, paramValue string) (*Environment, error) {
+	url := fmt.Sprintf("%s/environments", c.URL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -101,24 +121,16 @@ func getEnvironmentBy(c Client, paramKey string, paramValue string) (*Environmen
 	}
 
 	if len(environments.Items) == 0 {
-		return nil, fmt.Errorf("Environment with %s %s not found", paramKey, paramValue)
+		return nil, fmt.Errorf("Environment with %s %s %w", paramKey, paramValue, ErrNotFound)
 	} else {
 		return &environments.Items[0], nil
 	}
 }
 
-func (c *PrivateClient) GetEnvironmentByName(name string) (*Environment, error) {
-	return getEnvironmentBy(c, "name", name)
-}
-
-func (c *PublicClient) GetEnvironmentByName(name string) (*Environment, error) {
-	return getEnvironmentBy(c, "name", name)
-}
-
-func (c *PrivateClient) GetEnvironmentByConfluentID(confluentID string) (*Environment, error) {
-	return getEnvironmentBy(c, "confluentId", confluentID)
+func (c *APIClient) GetEnvironmentByName(ctx context.Context, name string) (*Environment, error) {
+	return c.getEnvironmentBy(ctx, "name", name)
 }
 
-func (c *PublicClient) GetEnvironmentByConfluentID(confluentID string) (*Environment, error) {
-	return getEnvironmentBy(c, "confluentId", confluentID)
+func (c *APIClient) GetEnvironmentByConfluentID(ctx context.Context, confluentID string) (*Environment, error) {
+	return c.getEnvironmentBy(ctx, "confluentId", confluentID)
 }
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrNotFound is returned when a lookup by name or other attribute matches
+// nothing.
+var ErrNotFound = errors.New("not found")
+
+// APIError is returned for every non-2xx response from Kafka Manager.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Body       []byte
+}
+
+type apiErrorBody struct {
+	Code    string `json:"code"`
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+func newAPIError(statusCode int, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: statusCode,
+		Body:       body,
+	}
+
+	var parsed apiErrorBody
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		e.Code = parsed.Code
+		if e.Code == "" {
+			e.Code = parsed.Error
+		}
+		e.Message = parsed.Message
+	}
+
+	return e
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("status: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound reports whether err means the requested object does not exist.
+func IsNotFound(err error) bool {
+	if errors.Is(err, ErrNotFound) {
+		return true
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	// Kafka Manager reports missing objects with codes such as
+	// "topics.does_not_exist", not always with a 404.
+	return apiErr.StatusCode == http.StatusNotFound ||
+		strings.HasSuffix(apiErr.Code, ".does_not_exist") ||
+		strings.HasSuffix(apiErr.Message, ".does_not_exist")
+}
+
+// IsForbidden reports whether err is an authorization failure.
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsConflict reports whether err is a conflict with the current state of the
+// object.
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsPreconditionFailed reports whether a conditional request was rejected
+// because the object changed since it was read.
+func IsPreconditionFailed(err error) bool {
+	return hasStatus(err, http.StatusPreconditionFailed)
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// maxLoggedBodyBytes caps how much of a request or response body is logged.
+const maxLoggedBodyBytes = 4096
+
+const redacted = "(redacted)"
+
+// sensitiveHeaders are never written to the log.
+var sensitiveHeaders = []string{
+	"Access-Token",
+	"Authorization",
+	"CAI-Data-Platform-Key",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+}
+
+// sensitiveFields are redacted from JSON and form-encoded bodies.
+var sensitiveFields = []string{
+	"access_token",
+	"client_secret",
+	"id_token",
+	"refresh_token",
+}
+
+type loggingTransport struct {
+	transport http.RoundTripper
+}
+
+// NewLoggingTransport wraps t so that every request and response is logged
+// at debug level, with credentials redacted.
+func NewLoggingTransport(t http.RoundTripper) http.RoundTripper {
+	return &loggingTransport{transport: t}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	var reqBody []byte
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ = ioutil.ReadAll(body)
+			body.Close()
+		}
+	}
+
+	start := time.Now()
+	res, err := t.transport.RoundTrip(req)
+	duration := time.Since(start)
+
+	args := []interface{}{
+		"method", req.Method,
+		"url", req.URL.String(),
+		"duration", duration.String(),
+		"request_headers", redactHeaders(req.Header),
+		"request_body", formatBody(req.Header.Get("Content-Type"), reqBody),
+	}
+
+	if err != nil {
+		tflog.Debug(ctx, "Kafka Manager API request failed", append(args, "error", err.Error())...)
+		return res, err
+	}
+
+	resBody, readErr := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+	if readErr != nil {
+		tflog.Debug(ctx, "Kafka Manager API request failed", append(args, "status", res.StatusCode, "error", readErr.Error())...)
+		return nil, readErr
+	}
+
+	tflog.Debug(ctx, "Kafka Manager API request", append(args,
+		"status", res.StatusCode,
+		"response_headers", redactHeaders(res.Header),
+		"response_body", formatBody(res.Header.Get("Content-Type"), resBody),
+	)...)
+
+	return res, nil
+}
+
+func redactHeaders(headers http.Header) map[string]string {
+	result := make(map[string]string, len(headers))
+	for key, values := range headers {
+		result[key] = strings.Join(values, ", ")
+	}
+	for _, key := range sensitiveHeaders {
+		if headers.Get(key) != "" {
+			result[http.CanonicalHeaderKey(key)] = redacted
+		}
+	}
+	return result
+}
+
+func formatBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	body = redactBody(contentType, body)
+	if len(body) > maxLoggedBodyBytes {
+		return string(body[:maxLoggedBodyBytes]) + "...(truncated)"
+	}
+	return string(body)
+}
+
+func redactBody(contentType string, body []byte) []byte {
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return []byte(redacted)
+		}
+		for _, field := range sensitiveFields {
+			if form.Get(field) != "" {
+				form.Set(field, redacted)
+			}
+		}
+		return []byte(form.Encode())
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(body, &object); err != nil {
+		return body
+	}
+	found := false
+	for _, field := range sensitiveFields {
+		if _, ok := object[field]; ok {
+			object[field] = redacted
+			found = true
+		}
+	}
+	if !found {
+		return body
+	}
+	redactedBody, err := json.Marshal(object)
+	if err != nil {
+		return []byte(redacted)
+	}
+	return redactedBody
+}
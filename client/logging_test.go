@@ -0,0 +1,46 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("Content-Type", "application/json")
+	headers.Set("CAI-Data-Platform-Key", "secret-key")
+	headers.Set("Access-Token", "secret-token")
+	headers.Set("Authorization", "Basic c2VjcmV0")
+
+	result := redactHeaders(headers)
+
+	if result["Content-Type"] != "application/json" {
+		t.Fatalf("expected Content-Type to be kept, got %q", result["Content-Type"])
+	}
+	for _, key := range []string{"Cai-Data-Platform-Key", "Access-Token", "Authorization"} {
+		if result[key] != redacted {
+			t.Errorf("expected %s to be redacted, got %q", key, result[key])
+		}
+	}
+	if headers.Get("Access-Token") != "secret-token" {
+		t.Fatal("redaction must not modify the request headers")
+	}
+}
+
+func TestFormatBody(t *testing.T) {
+	body := formatBody("application/json", []byte(`{"access_token": "secret", "expires_in": 3600}`))
+	if strings.Contains(body, "secret") || !strings.Contains(body, "3600") {
+		t.Fatalf("expected access_token to be redacted, got %s", body)
+	}
+
+	body = formatBody("application/x-www-form-urlencoded", []byte("grant_type=client_credentials&client_secret=secret"))
+	if strings.Contains(body, "secret&") || strings.Contains(body, "=secret") {
+		t.Fatalf("expected client_secret to be redacted, got %s", body)
+	}
+
+	body = formatBody("application/json", []byte(strings.Repeat("a", maxLoggedBodyBytes+10)))
+	if !strings.HasSuffix(body, "...(truncated)") || len(body) != maxLoggedBodyBytes+len("...(truncated)") {
+		t.Fatalf("expected body to be truncated, got %d bytes", len(body))
+	}
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenExpiryLeeway is how long before its expiry a cached token is
+// refreshed, so that it does not expire while a request is in flight.
+const tokenExpiryLeeway = 60 * time.Second
+
+// OAuth2Authenticator obtains access tokens with the OAuth2 client
+// credentials grant and sends them in the Access-Token header expected by
+// the Data Platform Gateway. Tokens are cached until shortly before they
+// expire.
+type OAuth2Authenticator struct {
+	HttpClient   *http.Client
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+}
+
+type oauth2TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func NewOAuth2Authenticator(tokenURL string, clientID string, clientSecret string, scopes []string) *OAuth2Authenticator {
+	return &OAuth2Authenticator{
+		HttpClient:   &http.Client{Transport: NewLoggingTransport(http.DefaultTransport)},
+		TokenURL:     tokenURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       scopes,
+	}
+}
+
+func (a *OAuth2Authenticator) Authenticate(req *http.Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == "" || !time.Now().Before(a.refreshAt) {
+		if err := a.fetchToken(req); err != nil {
+			return err
+		}
+	}
+
+	req.Header.Set("Access-Token", a.token)
+	return nil
+}
+
+// Invalidate drops the cached token so the next request fetches a new one.
+func (a *OAuth2Authenticator) Invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.token = ""
+	a.refreshAt = time.Time{}
+}
+
+func (a *OAuth2Authenticator) fetchToken(req *http.Request) error {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(a.Scopes) > 0 {
+		form.Set("scope", strings.Join(a.Scopes, " "))
+	}
+
+	tokenReq, err := http.NewRequestWithContext(req.Context(), "POST", a.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	tokenReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	tokenReq.Header.Set("Accept", "application/json")
+	tokenReq.SetBasicAuth(url.QueryEscape(a.ClientID), url.QueryEscape(a.ClientSecret))
+
+	res, err := a.HttpClient.Do(tokenReq)
+	if err != nil {
+		return fmt.Errorf("unable to fetch OAuth2 token: %w", err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("unable to fetch OAuth2 token: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to fetch OAuth2 token: %w", newAPIError(res.StatusCode, body))
+	}
+
+	var token oauth2TokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return fmt.Errorf("unable to parse OAuth2 token response: %s", err)
+	}
+	if token.AccessToken == "" {
+		return fmt.Errorf("OAuth2 token response from %s contains no access token", a.TokenURL)
+	}
+
+	a.token = token.AccessToken
+	lifetime := time.Duration(token.ExpiresIn) * time.Second
+	switch {
+	case lifetime <= 0:
+		// Without an expiry, rely on a 401 to trigger the refresh.
+		a.refreshAt = time.Now().Add(24 * time.Hour)
+	case lifetime > 2*tokenExpiryLeeway:
+		a.refreshAt = time.Now().Add(lifetime - tokenExpiryLeeway)
+	default:
+		a.refreshAt = time.Now().Add(lifetime / 2)
+	}
+
+	return nil
+}
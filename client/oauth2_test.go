@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func newTokenServer(t *testing.T, tokens *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("unable to parse token request: %s", err)
+		}
+		if grantType := r.PostForm.Get("grant_type"); grantType != "client_credentials" {
+			t.Errorf("expected client_credentials grant, got %q", grantType)
+		}
+		if scope := r.PostForm.Get("scope"); scope != "kafka.read kafka.write" {
+			t.Errorf("unexpected scope %q", scope)
+		}
+		if id, secret, ok := r.BasicAuth(); !ok || id != "client" || secret != "secret" {
+			t.Errorf("unexpected client credentials %q/%q", id, secret)
+		}
+		n := atomic.AddInt32(tokens, 1)
+		fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": 3600}`, n)
+	}))
+}
+
+func TestOAuth2Authenticator_CachesToken(t *testing.T) {
+	var tokens int32
+	tokenServer := newTokenServer(t, &tokens)
+	defer tokenServer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Access-Token"); got != "token-1" {
+			t.Errorf("expected token-1, got %q", got)
+		}
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, NewOAuth2Authenticator(tokenServer.URL, "client", "secret", []string{"kafka.read", "kafka.write"}))
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetCluster(context.Background(), 1); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if tokens != 1 {
+		t.Fatalf("expected 1 token request, got %d", tokens)
+	}
+}
+
+func TestOAuth2Authenticator_RefreshesAfterUnauthorized(t *testing.T) {
+	var tokens int32
+	tokenServer := newTokenServer(t, &tokens)
+	defer tokenServer.Close()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Header.Get("Access-Token") != "token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.ContentLength == 0 {
+			t.Error("retried request was sent without a body")
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, NewOAuth2Authenticator(tokenServer.URL, "client", "secret", []string{"kafka.read", "kafka.write"}))
+
+	if _, err := c.CreateTopic(context.Background(), &NewTopic{ClusterID: 1, Name: "orders"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if tokens != 2 || calls != 2 {
+		t.Fatalf("expected 2 token requests and 2 calls, got %d and %d", tokens, calls)
+	}
+}
+
+func TestOAuth2Authenticator_RetriesUnauthorizedOnce(t *testing.T) {
+	var tokens int32
+	tokenServer := newTokenServer(t, &tokens)
+	defer tokenServer.Close()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, NewOAuth2Authenticator(tokenServer.URL, "client", "secret", []string{"kafka.read", "kafka.write"}))
+
+	if _, err := c.GetCluster(context.Background(), 1); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+}
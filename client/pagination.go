@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const defaultPageSize = 100
+
+// listPage is the envelope Kafka Manager wraps around every list response.
+type listPage struct {
+	Items      json.RawMessage `json:"items"`
+	Page       int             `json:"page"`
+	Size       int             `json:"size"`
+	TotalPages int             `json:"totalPages"`
+	TotalItems int             `json:"totalItems"`
+}
+
+// pager fetches a list endpoint one page at a time.
+type pager struct {
+	ctx   context.Context
+	c     *APIClient
+	url   string
+	query url.Values
+	size  int
+	page  int
+	done  bool
+
+	// first is the ID of the first item of the previous page.
+	first string
+}
+
+func (c *APIClient) newPager(ctx context.Context, path string, query url.Values) *pager {
+	if query == nil {
+		query = url.Values{}
+	}
+	return &pager{
+		ctx:   ctx,
+		c:     c,
+		url:   fmt.Sprintf("%s%s", c.URL, path),
+		query: query,
+		size:  defaultPageSize,
+	}
+}
+
+// next decodes the items of the next page into items, which must be a
+// pointer to a slice. It returns false once every page has been read.
+func (p *pager) next(items interface{}) (bool, error) {
+	if p.done {
+		return false, nil
+	}
+
+	req, err := http.NewRequestWithContext(p.ctx, "GET", p.url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	q := url.Values{}
+	for key, values := range p.query {
+		q[key] = values
+	}
+	q.Set("page", strconv.Itoa(p.page))
+	q.Set("size", strconv.Itoa(p.size))
+	req.URL.RawQuery = q.Encode()
+
+	res, err := p.c.doRequest(req)
+	if err != nil {
+		return false, err
+	}
+
+	var page listPage
+	if err := json.Unmarshal(res, &page); err != nil {
+		return false, err
+	}
+
+	var count []struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if len(page.Items) > 0 {
+		if err := json.Unmarshal(page.Items, &count); err != nil {
+			return false, err
+		}
+	}
+	if len(count) == 0 {
+		p.done = true
+		return false, nil
+	}
+
+	// An endpoint that ignores paging returns the same page over and over.
+	first := string(count[0].ID)
+	if p.page > 0 && first != "" && first == p.first {
+		p.done = true
+		return false, nil
+	}
+	p.first = first
+
+	if err := json.Unmarshal(page.Items, items); err != nil {
+		return false, err
+	}
+
+	p.page++
+	switch {
+	case page.TotalPages > 0:
+		p.done = p.page >= page.TotalPages
+	default:
+		// Without paging metadata, a short page is the last one. A page larger
+		// than requested means the endpoint ignored paging and returned
+		// everything at once.
+		p.done = len(count) != p.size
+	}
+
+	return true, nil
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultMaxRetries   = 3
+	DefaultRetryMinWait = 1 * time.Second
+	DefaultRetryMaxWait = 30 * time.Second
+)
+
+// RetryPolicy controls how doRequest retries failed calls to Kafka Manager.
+type RetryPolicy struct {
+	MaxRetries int
+	MinWait    time.Duration
+	MaxWait    time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		MinWait:    DefaultRetryMinWait,
+		MaxWait:    DefaultRetryMaxWait,
+	}
+}
+
+// shouldRetry reports whether the attempt that produced res or err may be
+// repeated. Requests that are not idempotent are only retried when the
+// server is known not to have processed them.
+func (p RetryPolicy) shouldRetry(req *http.Request, res *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	// Once a conditional request has been applied, the entity tag no longer
+	// matches and a repeat fails with 412, which would be reported as a
+	// concurrent modification.
+	idempotent := isIdempotent(req.Method) && req.Header.Get("If-Match") == ""
+
+	if err != nil {
+		if idempotent {
+			return true
+		}
+		// The connection was never established, so the request was never sent.
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	default:
+		return false
+	}
+}
+
+// backoff returns how long to wait before the given retry attempt (starting
+// at 1). A Retry-After header on the response takes precedence over the
+// exponential backoff, but is still capped at MaxWait.
+func (p RetryPolicy) backoff(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if wait, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			if wait > p.MaxWait {
+				return p.MaxWait
+			}
+			return wait
+		}
+	}
+
+	ceiling := float64(p.MinWait) * math.Pow(2, float64(attempt-1))
+	if ceiling > float64(p.MaxWait) {
+		ceiling = float64(p.MaxWait)
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+
+	// Full jitter spreads out retries from parallel resources.
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		wait := time.Until(t)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// isIdempotent reports whether repeating a request with the given method is
+// safe. Kafka Manager PATCH bodies carry absolute values, so replaying them
+// has the same effect as sending them once.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
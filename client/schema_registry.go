@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -20,41 +21,68 @@ type SchemaRegistries struct {
 	Items []SchemaRegistry `json:"items"`
 }
 
-func getSchemaRegistries(c Client) ([]SchemaRegistry, error) {
-	url := fmt.Sprintf("%s/schema-registries", c.getURL())
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
+// SchemaRegistryIterator streams schema registries from Kafka Manager one page at a time.
+type SchemaRegistryIterator struct {
+	pager   *pager
+	items   []SchemaRegistry
+	current SchemaRegistry
+	err     error
+}
 
-	res, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
+// Next advances the iterator and reports whether another item is available.
+func (it *SchemaRegistryIterator) Next() bool {
+	for len(it.items) == 0 {
+		if it.err != nil {
+			return false
+		}
+		var items []SchemaRegistry
+		ok, err := it.pager.next(&items)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if !ok {
+			return false
+		}
+		it.items = items
 	}
 
-	var schemaRegistries SchemaRegistries
+	it.current = it.items[0]
+	it.items = it.items[1:]
+	return true
+}
 
-	err = json.Unmarshal(res, &schemaRegistries)
-	if err != nil {
-		return nil, err
-	}
+func (it *SchemaRegistryIterator) SchemaRegistry() SchemaRegistry {
+	return it.current
+}
 
-	return schemaRegistries.Items, nil
+func (it *SchemaRegistryIterator) Err() error {
+	return it.err
 }
 
-func (c *PrivateClient) GetSchemaRegistries() ([]SchemaRegistry, error) {
-	return getSchemaRegistries(c)
+func (c *APIClient) IterateSchemaRegistries(ctx context.Context) *SchemaRegistryIterator {
+	return &SchemaRegistryIterator{pager: c.newPager(ctx, "/schema-registries", nil)}
 }
 
-func (c *PublicClient) GetSchemaRegistries() ([]SchemaRegistry, error) {
-	return getSchemaRegistries(c)
+func (c *APIClient) GetSchemaRegistries(ctx context.Context) ([]SchemaRegistry, error) {
+	schemaRegistries := make([]SchemaRegistry, 0)
+
+	it := c.IterateSchemaRegistries(ctx)
+	for it.Next() {
+		schemaRegistries = append(schemaRegistries, it.SchemaRegistry())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return schemaRegistries, nil
 }
 
-func getSchemaRegistry(c Client, id int) (*SchemaRegistry, error) {
+func (c *APIClient) GetSchemaRegistry(ctx context.Context, id int) (*SchemaRegistry, error) {
 	var schemaRegistry SchemaRegistry
 
-	url := fmt.Sprintf("%s/schema-registries/%d", c.getURL(), id)
-	req, err := http.NewRequest("GET", url, nil)
+	url := fmt.Sprintf("%s/schema-registries/%d", c.URL, id)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -72,17 +100,9 @@ func getSchemaRegistry(c Client, id int) (*SchemaRegistry, error) {
 	return &schemaRegistry, nil
 }
 
-func (c *PrivateClient) GetSchemaRegistry(id int) (*SchemaRegistry, error) {
-	return getSchemaRegistry(c, id)
-}
-
-func (c *PublicClient) GetSchemaRegistry(id int) (*SchemaRegistry, error) {
-	return getSchemaRegistry(c, id)
-}
-
-func getSchemaRegistryBy(c Client, paramKey string, paramValue string) (*SchemaRegistry, error) {
-	url := fmt.Sprintf("%s/schema-registries", c.getURL())
-	req, err := http.NewRequest("GET", url, nil)
+func (c *APIClient) getSchemaRegistryBy(ctx context.Context, paramKey string, paramValue string) (*SchemaRegistry, error) {
+	url := fmt.Sprintf("%s/schema-registries", c.URL)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -104,16 +124,12 @@ func getSchemaRegistryBy(c Client, paramKey string, paramValue string) (*SchemaR
 	}
 
 	if len(schemaRegistries.Items) == 0 {
-		return nil, fmt.Errorf("SchemaRegistry with %s %s not found", paramKey, paramValue)
+		return nil, fmt.Errorf("SchemaRegistry with %s %s %w", paramKey, paramValue, ErrNotFound)
 	} else {
 		return &schemaRegistries.Items[0], nil
 	}
 }
 
-func (c *PrivateClient) GetSchemaRegistryByConfluentID(confluentID string) (*SchemaRegistry, error) {
-	return getSchemaRegistryBy(c, "confluentId", confluentID)
-}
-
-func (c *PublicClient) GetSchemaRegistryByConfluentID(confluentID string) (*SchemaRegistry, error) {
-	return getSchemaRegistryBy(c, "confluentId", confluentID)
+func (c *APIClient) GetSchemaRegistryByConfluentID(ctx context.Context, confluentID string) (*SchemaRegistry, error) {
+	return c.getSchemaRegistryBy(ctx, "confluentId", confluentID)
 }
@@ -2,12 +2,12 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
-	"time"
-	"math"
 )
 
 type Topics struct {
@@ -20,64 +20,226 @@ type Topic struct {
 	Name       string       `json:"name,omitempty"`
 	Partitions int          `json:"partitionsCount,omitempty"`
 	Config     *TopicConfig `json:"config,omitempty"`
+	Status     string       `json:"status,omitempty"`
+	Version    *int         `json:"version,omitempty"`
+
+	// Metadata for governance. As with TopicConfig, nil fields are left
+	// out of updates, and a nil tag removes the tag.
+	Description        *string            `json:"description,omitempty"`
+	Owner              *string            `json:"owner,omitempty"`
+	DataClassification *string            `json:"dataClassification,omitempty"`
+	Tags               map[string]*string `json:"tags,omitempty"`
+
+	// ETag is the entity tag GetTopic received with the topic. Listings do
+	// not carry one. When set, UpdateTopic only applies if the topic has not
+	// changed since.
+	ETag string `json:"-"`
 }
 
+// Kafka Manager provisions topics in Confluent asynchronously. Create,
+// update and delete return as soon as the change is accepted, and the topic
+// status tells when it has been applied.
+const (
+	TopicStatusProvisioning = "PROVISIONING"
+	TopicStatusReady        = "READY"
+	TopicStatusFailed       = "FAILED"
+	TopicStatusDeleting     = "DELETING"
+)
+
+// TopicConfig holds the settings of a topic. A nil field is not sent, so a
+// PATCH only carries the settings that changed; zero and -1 are sent as is.
+// Settings listed in Reset are sent as null, which restores the cluster
+// default.
 type TopicConfig struct {
-	ID                int    `json:"id,omitempty"`
-	ReplicationFactor int    `json:"replicationFactor,omitempty"`
-	MaxMessageBytes   int    `json:"maxMessageBytes,omitempty"`
-	CleanupPolicy     string `json:"cleanupPolicy,omitempty"`
-	RetentionMs       int    `json:"retentionMs,omitempty"`
-	RetentionBytes    int    `json:"retentionBytes,omitempty"`
+	ID                int     `json:"id,omitempty"`
+	ReplicationFactor *int    `json:"replicationFactor,omitempty"`
+	MaxMessageBytes   *int    `json:"maxMessageBytes,omitempty"`
+	CleanupPolicy     *string `json:"cleanupPolicy,omitempty"`
+	RetentionMs       *int    `json:"retentionMs,omitempty"`
+	RetentionBytes    *int    `json:"retentionBytes,omitempty"`
+
+	// Settings holds any other Kafka topic config by its Kafka name, such
+	// as min.insync.replicas. A nil value resets the config to the cluster
+	// default.
+	Settings map[string]*string `json:"settings,omitempty"`
+
+	Reset []string `json:"-"`
+}
+
+// JSON names of the topic settings, for use in TopicConfig.Reset.
+const (
+	TopicConfigReplicationFactor = "replicationFactor"
+	TopicConfigMaxMessageBytes   = "maxMessageBytes"
+	TopicConfigCleanupPolicy     = "cleanupPolicy"
+	TopicConfigRetentionMs       = "retentionMs"
+	TopicConfigRetentionBytes    = "retentionBytes"
+)
+
+func (c TopicConfig) MarshalJSON() ([]byte, error) {
+	type topicConfig TopicConfig
+	j, err := json.Marshal(topicConfig(c))
+	if err != nil || len(c.Reset) == 0 {
+		return j, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(j, &fields); err != nil {
+		return nil, err
+	}
+	for _, name := range c.Reset {
+		fields[name] = json.RawMessage("null")
+	}
+	return json.Marshal(fields)
+}
+
+// Int returns a pointer to v, for use in TopicConfig.
+func Int(v int) *int {
+	return &v
+}
+
+// String returns a pointer to v, for use in TopicConfig.
+func String(v string) *string {
+	return &v
 }
 
 type NewTopic struct {
-	ClusterID  int          `json:"clusterId"`
-	Name       string       `json:"name"`
-	Partitions int          `json:"partitionsCount"`
-	Config     *TopicConfig `json:"config"`
+	ClusterID          int               `json:"clusterId"`
+	Name               string            `json:"name"`
+	Partitions         int               `json:"partitionsCount"`
+	Config             *TopicConfig      `json:"config"`
+	Description        string            `json:"description,omitempty"`
+	Owner              string            `json:"owner,omitempty"`
+	DataClassification string            `json:"dataClassification,omitempty"`
+	Tags               map[string]string `json:"tags,omitempty"`
 }
 
+// Data classifications of topics, from least to most sensitive.
+const (
+	DataClassificationPublic       = "public"
+	DataClassificationInternal     = "internal"
+	DataClassificationConfidential = "confidential"
+	DataClassificationRestricted   = "restricted"
+)
+
 const topicResourcePath string = "/topics"
 
-func getTopics(c Client) ([]Topic, error) {
-	url := fmt.Sprintf("%s%s", c.getURL(), topicResourcePath)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
+// TopicIterator streams topics from Kafka Manager one page at a time.
+type TopicIterator struct {
+	pager   *pager
+	items   []Topic
+	current Topic
+	err     error
+}
 
-	res, err := c.doRequest(req)
-	if err != nil {
-		return nil, err
+// Next advances the iterator and reports whether another item is available.
+func (it *TopicIterator) Next() bool {
+	for len(it.items) == 0 {
+		if it.err != nil {
+			return false
+		}
+		var items []Topic
+		ok, err := it.pager.next(&items)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if !ok {
+			return false
+		}
+		it.items = items
 	}
 
-	var topics Topics
+	it.current = it.items[0]
+	it.items = it.items[1:]
+	return true
+}
 
-	err = json.Unmarshal(res, &topics)
-	if err != nil {
-		return nil, err
-	}
+func (it *TopicIterator) Topic() Topic {
+	return it.current
+}
+
+func (it *TopicIterator) Err() error {
+	return it.err
+}
 
-	return topics.Items, nil
+func (c *APIClient) IterateTopics(ctx context.Context) *TopicIterator {
+	return &TopicIterator{pager: c.newPager(ctx, topicResourcePath, nil)}
 }
 
-func (c *PrivateClient) GetTopics() ([]Topic, error) {
-	return getTopics(c)
+func (c *APIClient) GetTopics(ctx context.Context) ([]Topic, error) {
+	return c.ListTopics(ctx, ListTopicsOptions{})
 }
 
-func (c *PublicClient) GetTopics() ([]Topic, error) {
-	return getTopics(c)
+// ListTopicsOptions narrows a topic listing down on the server side. Zero
+// values are not sent.
+type ListTopicsOptions struct {
+	ClusterID          int
+	EnvironmentID      int
+	NamePrefix         string
+	Supplier           string
+	Owner              string
+	DataClassification string
+	// Tags only returns topics that have all of these tags.
+	Tags           map[string]string
+	SortBy         string
+	SortDescending bool
 }
 
-func getTopic(c Client, id int) (*Topic, error) {
-	url := fmt.Sprintf("%s%s/%d", c.getURL(), topicResourcePath, id)
-	req, err := http.NewRequest("GET", url, nil)
+func (o ListTopicsOptions) query() url.Values {
+	q := url.Values{}
+	if o.ClusterID != 0 {
+		q.Set("cluster.id", strconv.Itoa(o.ClusterID))
+	}
+	if o.EnvironmentID != 0 {
+		q.Set("cluster.environment.id", strconv.Itoa(o.EnvironmentID))
+	}
+	if o.NamePrefix != "" {
+		q.Set("name.startsWith", o.NamePrefix)
+	}
+	if o.Supplier != "" {
+		q.Set("cluster.environment.supplier", o.Supplier)
+	}
+	if o.Owner != "" {
+		q.Set("owner", o.Owner)
+	}
+	if o.DataClassification != "" {
+		q.Set("dataClassification", o.DataClassification)
+	}
+	for key, value := range o.Tags {
+		q.Set("tags."+key, value)
+	}
+	if o.SortBy != "" {
+		direction := "asc"
+		if o.SortDescending {
+			direction = "desc"
+		}
+		q.Set("sort", fmt.Sprintf("%s,%s", o.SortBy, direction))
+	}
+	return q
+}
+
+func (c *APIClient) ListTopics(ctx context.Context, opts ListTopicsOptions) ([]Topic, error) {
+	topics := make([]Topic, 0)
+
+	it := &TopicIterator{pager: c.newPager(ctx, topicResourcePath, opts.query())}
+	for it.Next() {
+		topics = append(topics, it.Topic())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return topics, nil
+}
+
+func (c *APIClient) GetTopic(ctx context.Context, id int) (*Topic, error) {
+	url := fmt.Sprintf("%s%s/%d", c.URL, topicResourcePath, id)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := c.doRequest(req)
+	res, headers, err := c.doRequestWithHeaders(req)
 	if err != nil {
 		return nil, err
 	}
@@ -88,21 +250,27 @@ func getTopic(c Client, id int) (*Topic, error) {
 	if err != nil {
 		return nil, err
 	}
+	topic.ETag = headers.Get("ETag")
 
 	return &topic, nil
 }
 
-func (c *PrivateClient) GetTopic(id int) (*Topic, error) {
-	return getTopic(c, id)
+// GetTopicInCluster reads a topic that is known to belong to the given
+// cluster. Implementations may use the cluster to batch reads.
+func (c *APIClient) GetTopicInCluster(ctx context.Context, clusterID int, id int) (*Topic, error) {
+	return c.GetTopic(ctx, id)
 }
 
-func (c *PublicClient) GetTopic(id int) (*Topic, error) {
-	return getTopic(c, id)
+// ListTopicsInCluster lists the topics of the cluster. Implementations may
+// answer from a listing taken earlier, so the result can miss recent
+// changes.
+func (c *APIClient) ListTopicsInCluster(ctx context.Context, clusterID int) ([]Topic, error) {
+	return c.ListTopics(ctx, ListTopicsOptions{ClusterID: clusterID})
 }
 
-func getTopicByNameAndClusterID(c Client, name string, clusterID int) (*Topic, error) {
-	url := fmt.Sprintf("%s%s", c.getURL(), topicResourcePath)
-	req, err := http.NewRequest("GET", url, nil)
+func (c *APIClient) GetTopicByNameAndClusterID(ctx context.Context, name string, clusterID int) (*Topic, error) {
+	url := fmt.Sprintf("%s%s", c.URL, topicResourcePath)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -125,49 +293,28 @@ func getTopicByNameAndClusterID(c Client, name string, clusterID int) (*Topic, e
 	}
 
 	if len(topics.Items) == 0 {
-		return nil, fmt.Errorf("cannot find topic with name %s and cluster ID %d", name, clusterID)
+		return nil, fmt.Errorf("topic with name %s and cluster ID %d %w", name, clusterID, ErrNotFound)
 	} else {
 		return &topics.Items[0], nil
 	}
 
 }
 
-func (c *PrivateClient) GetTopicByNameAndClusterID(name string, clusterID int) (*Topic, error) {
-	return getTopicByNameAndClusterID(c, name, clusterID)
-}
-
-func (c *PublicClient) GetTopicByNameAndClusterID(name string, clusterID int) (*Topic, error) {
-	return getTopicByNameAndClusterID(c, name, clusterID)
-}
-
-func createTopic(c Client, t *NewTopic) (*Topic, error) {
+func (c *APIClient) CreateTopic(ctx context.Context, t *NewTopic) (*Topic, error) {
 	j, err := json.Marshal(t)
 	if err != nil {
 		return nil, err
 	}
-	url := fmt.Sprintf("%s%s", c.getURL(), topicResourcePath)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(j))
+	url := fmt.Sprintf("%s%s", c.URL, topicResourcePath)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(j))
 	if err != nil {
 		return nil, err
 	}
 
-    retryCap := 3
-    res, err := c.doRequest(req)
-    for retry := 1; retry <= retryCap; retry++{
-        if err != nil {
-            fmt.Printf("%s\n\n",err)
-            fmt.Println("Waiting to retry...")
-            time.Sleep(time.Duration(math.Pow(float64(retry), 2.5)) * time.Second)
-            fmt.Printf("Starting retry attempt %d of %d\n", retry, retryCap)
-            res, err := c.doRequest(req)
-            _,_ = res, err
-        } else {
-            break
-        }
-    }
-    if err != nil {
-        return nil, err
-    }
+	res, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
 
 	var topic Topic
 	err = json.Unmarshal(res, &topic)
@@ -178,16 +325,8 @@ func createTopic(c Client, t *NewTopic) (*Topic, error) {
 	return &topic, nil
 }
 
-func (c *PrivateClient) CreateTopic(t *NewTopic) (*Topic, error) {
-	return createTopic(c, t)
-}
-
-func (c *PublicClient) CreateTopic(t *NewTopic) (*Topic, error) {
-	return createTopic(c, t)
-}
-
-func updateTopic(c Client, t *Topic) error {
-	url := fmt.Sprintf("%s%s/%d", c.getURL(), topicResourcePath, t.ID)
+func (c *APIClient) UpdateTopic(ctx context.Context, t *Topic) error {
+	url := fmt.Sprintf("%s%s/%d", c.URL, topicResourcePath, t.ID)
 	//WORKAROUND: Kafka Manager doesn't like the id field in PATCH requests.
 	t.ID = 0
 
@@ -196,10 +335,13 @@ func updateTopic(c Client, t *Topic) error {
 		return err
 	}
 
-	req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(j))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewBuffer(j))
 	if err != nil {
 		return err
 	}
+	if t.ETag != "" {
+		req.Header.Set("If-Match", t.ETag)
+	}
 
 	_, err = c.doRequest(req)
 	if err != nil {
@@ -209,20 +351,17 @@ func updateTopic(c Client, t *Topic) error {
 	return nil
 }
 
-func (c *PrivateClient) UpdateTopic(t *Topic) error {
-	return updateTopic(c, t)
-}
-
-func (c *PublicClient) UpdateTopic(t *Topic) error {
-	return updateTopic(c, t)
-}
-
-func deleteTopic(c Client, id int) error {
-	url := fmt.Sprintf("%s%s/%d", c.getURL(), topicResourcePath, id)
-	req, err := http.NewRequest("DELETE", url, nil)
+// DeleteTopic deletes the topic. A non-empty etag makes the deletion
+// conditional on the topic not having changed since it was read.
+func (c *APIClient) DeleteTopic(ctx context.Context, id int, etag string) error {
+	url := fmt.Sprintf("%s%s/%d", c.URL, topicResourcePath, id)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
 
 	_, err = c.doRequest(req)
 	if err != nil {
@@ -231,11 +370,3 @@ func deleteTopic(c Client, id int) error {
 
 	return nil
 }
-
-func (c *PrivateClient) DeleteTopic(id int) error {
-	return deleteTopic(c, id)
-}
-
-func (c *PublicClient) DeleteTopic(id int) error {
-	return deleteTopic(c, id)
-}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTopicConfig_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		config TopicConfig
+		want   string
+	}{
+		{
+			name:   "unset",
+			config: TopicConfig{},
+			want:   `{}`,
+		},
+		{
+			name:   "zero and infinite",
+			config: TopicConfig{RetentionMs: Int(0), RetentionBytes: Int(-1)},
+			want:   `{"retentionMs":0,"retentionBytes":-1}`,
+		},
+		{
+			name:   "settings",
+			config: TopicConfig{Settings: map[string]*string{"min.insync.replicas": String("2"), "segment.ms": nil}},
+			want:   `{"settings":{"min.insync.replicas":"2","segment.ms":null}}`,
+		},
+		{
+			name:   "reset",
+			config: TopicConfig{MaxMessageBytes: Int(1024), Reset: []string{TopicConfigRetentionMs}},
+			want:   `{"maxMessageBytes":1024,"retentionMs":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(&Topic{Config: &tt.config})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if want := `{"config":` + tt.want + `}`; string(got) != want {
+				t.Fatalf("expected %s, got %s", want, got)
+			}
+		})
+	}
+}
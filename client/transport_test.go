@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClient_TrustsCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "kafkamanager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	caFile := filepath.Join(dir, "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := ioutil.WriteFile(caFile, caPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewClient(server.URL, nil)
+	c.RetryPolicy = RetryPolicy{}
+	if _, err := c.GetCluster(context.Background(), 1); err == nil {
+		t.Fatal("expected the default client to reject the test certificate")
+	}
+
+	c.HttpClient, err = NewHTTPClient(TransportConfig{CACertFile: caFile})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := c.GetCluster(context.Background(), 1); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestNewHTTPClient_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	httpClient, err := NewHTTPClient(TransportConfig{Timeout: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	c := NewClient(server.URL, nil)
+	c.HttpClient = httpClient
+	c.RetryPolicy = RetryPolicy{}
+	if _, err := c.GetCluster(context.Background(), 1); err == nil {
+		t.Fatal("expected the request to time out")
+	}
+}
+
+func TestNewHTTPClient_InvalidConfig(t *testing.T) {
+	if _, err := NewHTTPClient(TransportConfig{CACertFile: "does-not-exist.pem"}); err == nil {
+		t.Fatal("expected an error for a missing CA bundle")
+	}
+	if _, err := NewHTTPClient(TransportConfig{ClientCertFile: "cert.pem"}); err == nil {
+		t.Fatal("expected an error for a client certificate without a key")
+	}
+}
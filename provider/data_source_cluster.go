@@ -87,17 +87,17 @@ func dataSourceClusterRead(ctx context.Context, d *schema.ResourceData, meta int
 		if err != nil {
 			return diag.Errorf("invalid cluster ID: %s", err)
 		}
-		rawCluster, err = c.GetCluster(id.(int))
+		rawCluster, err = c.GetCluster(ctx, id.(int))
 		if err != nil {
 			return diag.FromErr(err)
 		}
 	} else if confluentID, ok := d.GetOk("confluent_id"); ok {
-		rawCluster, err = c.GetClusterByConfluentID(confluentID.(string))
+		rawCluster, err = c.GetClusterByConfluentID(ctx, confluentID.(string))
 		if err != nil {
 			return diag.FromErr(err)
 		}
 	} else if name, ok := d.GetOk("name"); ok {
-		rawCluster, err = c.GetClusterByName(name.(string))
+		rawCluster, err = c.GetClusterByName(ctx, name.(string))
 		if err != nil {
 			return diag.FromErr(err)
 		}
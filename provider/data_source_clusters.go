@@ -34,7 +34,7 @@ func dataSourceClusters() *schema.Resource {
 func dataSourceClustersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(client.Client)
 
-	rawClusters, err := c.GetClusters()
+	rawClusters, err := c.GetClusters(ctx)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -57,17 +57,17 @@ func dataSourceEnvironmentRead(ctx context.Context, d *schema.ResourceData, m in
 		if err != nil {
 			return diag.Errorf("invalid environment ID: %s", err)
 		}
-		rawEnvironment, err = c.GetEnvironment(id.(int))
+		rawEnvironment, err = c.GetEnvironment(ctx, id.(int))
 		if err != nil {
 			return diag.FromErr(err)
 		}
 	} else if confluentID, ok := d.GetOk("confluent_id"); ok {
-		rawEnvironment, err = c.GetEnvironmentByConfluentID(confluentID.(string))
+		rawEnvironment, err = c.GetEnvironmentByConfluentID(ctx, confluentID.(string))
 		if err != nil {
 			return diag.FromErr(err)
 		}
 	} else if name, ok := d.GetOk("name"); ok {
-		rawEnvironment, err = c.GetEnvironmentByName(name.(string))
+		rawEnvironment, err = c.GetEnvironmentByName(ctx, name.(string))
 		if err != nil {
 			return diag.FromErr(err)
 		}
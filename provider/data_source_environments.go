@@ -34,7 +34,7 @@ func dataSourceEnvironments() *schema.Resource {
 func dataSourceEnvironmentsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(client.Client)
 
-	rawEnvironments, err := c.GetEnvironments()
+	rawEnvironments, err := c.GetEnvironments(ctx)
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -34,7 +34,7 @@ func dataSourceSchemaRegistries() *schema.Resource {
 func dataSourceSchemaRegistriesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(client.Client)
 
-	rawSchemaRegistries, err := client.GetSchemaRegistries()
+	rawSchemaRegistries, err := client.GetSchemaRegistries(ctx)
 	if err != nil {
 		return diag.FromErr(err)
 	}
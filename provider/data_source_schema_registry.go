@@ -64,12 +64,12 @@ func dataSourceSchemaRegistryRead(ctx context.Context, d *schema.ResourceData, m
 		if err != nil {
 			return diag.Errorf("invalid schema registry ID: %s", err)
 		}
-		rawSchemaRegistry, err = c.GetSchemaRegistry(id.(int))
+		rawSchemaRegistry, err = c.GetSchemaRegistry(ctx, id.(int))
 		if err != nil {
 			return diag.FromErr(err)
 		}
 	} else if confluentID, ok := d.GetOk("confluent_id"); ok {
-		rawSchemaRegistry, err = c.GetSchemaRegistryByConfluentID(confluentID.(string))
+		rawSchemaRegistry, err = c.GetSchemaRegistryByConfluentID(ctx, confluentID.(string))
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -7,21 +7,33 @@ import (
 	"coxautoinc.com/data-platform/kafka-manager/client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func dataSourceTopic() *schema.Resource {
 	recordSchema := topicSchema()
 
+	// Topics are looked up as they are, so their values are not validated.
 	for _, f := range recordSchema {
 		f.Computed = true
+		f.ValidateFunc = nil
 	}
 
-	recordSchema["id"].ExactlyOneOf = []string{"id", "name"}
+	// A topic is found by ID, by name, or as the only topic with the given
+	// metadata. Metadata given along with an ID or name must match.
+	lookup := []string{"id", "name", "owner", "data_classification", "tags"}
+
+	recordSchema["id"].AtLeastOneOf = lookup
+	recordSchema["id"].ConflictsWith = []string{"name"}
 	recordSchema["id"].Optional = true
-	recordSchema["name"].ExactlyOneOf = []string{"id", "name"}
+	recordSchema["name"].AtLeastOneOf = lookup
 	recordSchema["name"].Optional = true
 	recordSchema["cluster_id"].RequiredWith = []string{"name"}
 	recordSchema["cluster_id"].Optional = true
+	recordSchema["owner"].Optional = true
+	recordSchema["data_classification"].Optional = true
+	recordSchema["data_classification"].ValidateFunc = validation.StringInSlice(dataClassifications, false)
+	recordSchema["tags"].Optional = true
 
 	return &schema.Resource{
 		Schema:      recordSchema,
@@ -40,7 +52,7 @@ func dataSourceTopicRead(ctx context.Context, d *schema.ResourceData, meta inter
 		if err != nil {
 			return diag.Errorf("invalid topic ID: %s", err)
 		}
-		rawTopic, err = c.GetTopic(id.(int))
+		rawTopic, err = c.GetTopic(ctx, id.(int))
 		if err != nil {
 			return diag.FromErr(err)
 		}
@@ -50,7 +62,7 @@ func dataSourceTopicRead(ctx context.Context, d *schema.ResourceData, meta inter
 			if err != nil {
 				return diag.Errorf("invalid cluster ID: %s", err)
 			}
-			rawTopic, err = c.GetTopicByNameAndClusterID(name.(string), clusterID.(int))
+			rawTopic, err = c.GetTopicByNameAndClusterID(ctx, name.(string), clusterID.(int))
 			if err != nil {
 				return diag.FromErr(err)
 			}
@@ -58,7 +70,31 @@ func dataSourceTopicRead(ctx context.Context, d *schema.ResourceData, meta inter
 			return diag.Errorf("provide both topic name and cluster id")
 		}
 	} else {
-		return diag.Errorf("provide topic id, or topic name and cluster id")
+		opts := unmarshalTopicMetadataFilter(d)
+		if v, ok := d.GetOk("cluster_id"); ok {
+			opts.ClusterID, err = strconv.Atoi(v.(string))
+			if err != nil {
+				return diag.Errorf("invalid cluster ID: %s", err)
+			}
+		}
+		listed, err := c.ListTopics(ctx, opts)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		var topics []client.Topic
+		for i := range listed {
+			if matchesListTopicsOptions(&listed[i], opts) {
+				topics = append(topics, listed[i])
+			}
+		}
+		if len(topics) != 1 {
+			return diag.Errorf("expected exactly one topic with the given owner, data classification and tags, found %d", len(topics))
+		}
+		rawTopic = &topics[0]
+	}
+
+	if !matchesTopicMetadataFilter(rawTopic, unmarshalTopicMetadataFilter(d)) {
+		return diag.Errorf("topic %s does not have the given owner, data classification and tags", rawTopic.Name)
 	}
 
 	topic, err := marshalTopic(rawTopic)
@@ -73,3 +109,34 @@ func dataSourceTopicRead(ctx context.Context, d *schema.ResourceData, meta inter
 
 	return nil
 }
+
+// unmarshalTopicMetadataFilter reads the metadata filters shared by the topic
+// data sources.
+func unmarshalTopicMetadataFilter(d *schema.ResourceData) client.ListTopicsOptions {
+	opts := client.ListTopicsOptions{
+		Owner:              d.Get("owner").(string),
+		DataClassification: d.Get("data_classification").(string),
+	}
+	if v, ok := d.GetOk("tags"); ok {
+		opts.Tags = make(map[string]string)
+		for key, value := range v.(map[string]interface{}) {
+			opts.Tags[key] = value.(string)
+		}
+	}
+	return opts
+}
+
+func matchesTopicMetadataFilter(t *client.Topic, opts client.ListTopicsOptions) bool {
+	if opts.Owner != "" && (t.Owner == nil || *t.Owner != opts.Owner) {
+		return false
+	}
+	if opts.DataClassification != "" && (t.DataClassification == nil || *t.DataClassification != opts.DataClassification) {
+		return false
+	}
+	for key, value := range opts.Tags {
+		if tag := t.Tags[key]; tag == nil || *tag != value {
+			return false
+		}
+	}
+	return true
+}
@@ -2,23 +2,71 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"coxautoinc.com/data-platform/kafka-manager/client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func dataSourceTopics() *schema.Resource {
 	recordSchema := topicSchema()
 
+	// Topics are looked up as they are, so their values are not validated.
 	for _, f := range recordSchema {
 		f.Computed = true
+		f.ValidateFunc = nil
 	}
 
 	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
+			"cluster_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"environment_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name_prefix": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"supplier": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"owner": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"data_classification": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(dataClassifications, false),
+			},
+			"tags": &schema.Schema{
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"sort_by": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"id", "name"}, false),
+			},
+			"sort_order": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "asc",
+				ValidateFunc: validation.StringInSlice([]string{"asc", "desc"}, false),
+			},
 			"topics": &schema.Schema{
 				Computed: true,
 				Type:     schema.TypeList,
@@ -32,12 +80,23 @@ func dataSourceTopics() *schema.Resource {
 }
 
 func dataSourceTopicsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(client.Client)
+	c := m.(client.Client)
+
+	opts, err := unmarshalListTopicsOptions(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-	rawTopics, err := client.GetTopics()
+	listed, err := c.ListTopics(ctx, *opts)
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	rawTopics := make([]client.Topic, 0, len(listed))
+	for i := range listed {
+		if matchesListTopicsOptions(&listed[i], *opts) {
+			rawTopics = append(rawTopics, listed[i])
+		}
+	}
 
 	topics, err := marshalTopics(&rawTopics)
 	if err != nil {
@@ -54,6 +113,55 @@ func dataSourceTopicsRead(ctx context.Context, d *schema.ResourceData, m interfa
 	return nil
 }
 
+func unmarshalListTopicsOptions(d *schema.ResourceData) (*client.ListTopicsOptions, error) {
+	opts := unmarshalTopicMetadataFilter(d)
+	opts.NamePrefix = d.Get("name_prefix").(string)
+	opts.Supplier = d.Get("supplier").(string)
+	opts.SortBy = d.Get("sort_by").(string)
+	opts.SortDescending = d.Get("sort_order").(string) == "desc"
+
+	if v, ok := d.GetOk("cluster_id"); ok {
+		clusterID, err := strconv.Atoi(v.(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cluster ID: %s", err)
+		}
+		opts.ClusterID = clusterID
+	}
+	if v, ok := d.GetOk("environment_id"); ok {
+		environmentID, err := strconv.Atoi(v.(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid environment ID: %s", err)
+		}
+		opts.EnvironmentID = environmentID
+	}
+
+	return &opts, nil
+}
+
+// matchesListTopicsOptions reports whether t passes the filters of opts.
+// Kafka Manager versions that do not know a filter ignore it, so the
+// filters are applied to the results as well.
+func matchesListTopicsOptions(t *client.Topic, opts client.ListTopicsOptions) bool {
+	if opts.NamePrefix != "" && !strings.HasPrefix(t.Name, opts.NamePrefix) {
+		return false
+	}
+	if opts.ClusterID != 0 || opts.EnvironmentID != 0 || opts.Supplier != "" {
+		if t.Cluster == nil {
+			return false
+		}
+		if opts.ClusterID != 0 && t.Cluster.ID != opts.ClusterID {
+			return false
+		}
+		if opts.EnvironmentID != 0 && t.Cluster.Environment.ID != opts.EnvironmentID {
+			return false
+		}
+		if opts.Supplier != "" && !strings.EqualFold(t.Cluster.Environment.Supplier, opts.Supplier) {
+			return false
+		}
+	}
+	return matchesTopicMetadataFilter(t, opts)
+}
+
 func marshalTopics(topics *[]client.Topic) ([]map[string]interface{}, error) {
 	result := make([]map[string]interface{}, 0)
 
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"testing"
+
+	"coxautoinc.com/data-platform/kafka-manager/client"
+)
+
+func TestMatchesListTopicsOptions(t *testing.T) {
+	topic := &client.Topic{
+		Name: "orders.created",
+		Cluster: &client.Cluster{
+			ID:          10,
+			Environment: client.Environment{ID: 7, Supplier: "Confluent"},
+		},
+		Owner: client.String("data-platform"),
+		Tags:  map[string]*string{"domain": client.String("sales")},
+	}
+
+	tests := []struct {
+		name string
+		opts client.ListTopicsOptions
+		want bool
+	}{
+		{"no filters", client.ListTopicsOptions{}, true},
+		{"all match", client.ListTopicsOptions{NamePrefix: "orders.", ClusterID: 10, EnvironmentID: 7, Supplier: "confluent", Owner: "data-platform", Tags: map[string]string{"domain": "sales"}}, true},
+		{"name prefix", client.ListTopicsOptions{NamePrefix: "payments."}, false},
+		{"cluster", client.ListTopicsOptions{ClusterID: 11}, false},
+		{"environment", client.ListTopicsOptions{EnvironmentID: 8}, false},
+		{"supplier", client.ListTopicsOptions{Supplier: "AWS"}, false},
+		{"owner", client.ListTopicsOptions{Owner: "payments"}, false},
+		{"data classification", client.ListTopicsOptions{DataClassification: client.DataClassificationRestricted}, false},
+		{"tags", client.ListTopicsOptions{Tags: map[string]string{"domain": "billing"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesListTopicsOptions(topic, tt.opts); got != tt.want {
+				t.Errorf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package provider
 import (
 	"fmt"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -14,3 +15,54 @@ func setResourceDataFromMap(d *schema.ResourceData, m map[string]interface{}) er
 	}
 	return nil
 }
+
+// isConfigured reports whether the attribute has a value in the plan being
+// applied or, when refreshing, in the prior state. Unlike d.GetOk, it tells
+// an explicit zero apart from an attribute that is not set. A computed
+// attribute left unknown in the plan is not set.
+func isConfigured(d *schema.ResourceData, key string) bool {
+	if plan := d.GetRawPlan(); !plan.IsNull() {
+		if isRawAttributeSet(d.GetRawConfig(), key) {
+			return true
+		}
+		v := plan.GetAttr(key)
+		return v.IsKnown() && !v.IsNull()
+	}
+	if state := d.GetRawState(); !state.IsNull() {
+		return isRawAttributeSet(state, key)
+	}
+	_, ok := d.GetOk(key)
+	return ok
+}
+
+// hasChange is d.HasChange, but also reports an attribute that was added or
+// removed while keeping its zero value.
+func hasChange(d *schema.ResourceData, key string) bool {
+	return d.HasChange(key) || isRawAttributeSet(d.GetRawState(), key) != isRawAttributeSet(d.GetRawPlan(), key)
+}
+
+func isRawAttributeSet(v cty.Value, key string) bool {
+	if v.IsNull() || !v.IsKnown() {
+		return false
+	}
+	return !v.GetAttr(key).IsNull()
+}
+
+// stateInt returns the value of an integer attribute in the prior state.
+// Unlike d.GetOk, it tells an explicit zero apart from an attribute that is
+// not set.
+func stateInt(d *schema.ResourceData, key string) (int, bool) {
+	state := d.GetRawState()
+	if state.IsNull() {
+		v, ok := d.GetOk(key)
+		if !ok {
+			return 0, false
+		}
+		return v.(int), true
+	}
+	if !isRawAttributeSet(state, key) {
+		return 0, false
+	}
+	v, _ := state.GetAttr(key).AsBigFloat().Int64()
+	return int(v), true
+}
@@ -2,10 +2,13 @@ package provider
 
 import (
 	"context"
+	"math"
+	"time"
 
 	"coxautoinc.com/data-platform/kafka-manager/client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func Provider() *schema.Provider {
@@ -20,39 +23,157 @@ func Provider() *schema.Provider {
 				Type:          schema.TypeString,
 				Optional:      true,
 				Sensitive:     true,
-				ConflictsWith: []string{"key", "okta_groups", "supplier", "user_id"},
-				ExactlyOneOf:  []string{"access_token", "key"},
+				ConflictsWith: []string{"key", "okta_groups", "supplier", "user_id", "client_id"},
+				ExactlyOneOf:  []string{"access_token", "key", "client_id"},
 				DefaultFunc:   schema.EnvDefaultFunc("KAFKAMANAGER_ACCESS_TOKEN", nil),
 			},
 			"key": &schema.Schema{
 				Type:          schema.TypeString,
 				Optional:      true,
 				Sensitive:     true,
-				ConflictsWith: []string{"access_token"},
-				ExactlyOneOf:  []string{"access_token", "key"},
+				ConflictsWith: []string{"access_token", "client_id"},
+				ExactlyOneOf:  []string{"access_token", "key", "client_id"},
 				DefaultFunc:   schema.EnvDefaultFunc("KAFKAMANAGER_KEY", nil),
 			},
 			"okta_groups": &schema.Schema{
 				Type:          schema.TypeString,
 				Optional:      true,
-				ConflictsWith: []string{"access_token"},
+				ConflictsWith: []string{"access_token", "client_id"},
 				RequiredWith:  []string{"key"},
 				DefaultFunc:   schema.EnvDefaultFunc("KAFKAMANAGER_OKTA_GROUPS", nil),
 			},
 			"supplier": &schema.Schema{
 				Type:          schema.TypeString,
 				Optional:      true,
-				ConflictsWith: []string{"access_token"},
+				ConflictsWith: []string{"access_token", "client_id"},
 				RequiredWith:  []string{"key"},
 				DefaultFunc:   schema.EnvDefaultFunc("KAFKAMANAGER_SUPPLIER", nil),
 			},
 			"user_id": &schema.Schema{
 				Type:          schema.TypeString,
 				Optional:      true,
-				ConflictsWith: []string{"access_token"},
+				ConflictsWith: []string{"access_token", "client_id"},
 				RequiredWith:  []string{"key"},
 				DefaultFunc:   schema.EnvDefaultFunc("KAFKAMANAGER_USER_ID", nil),
 			},
+			"oauth_token_url": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"client_id"},
+				DefaultFunc:  schema.EnvDefaultFunc("KAFKAMANAGER_OAUTH_TOKEN_URL", nil),
+			},
+			"client_id": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"access_token", "key"},
+				ExactlyOneOf:  []string{"access_token", "key", "client_id"},
+				RequiredWith:  []string{"oauth_token_url", "client_secret"},
+				DefaultFunc:   schema.EnvDefaultFunc("KAFKAMANAGER_CLIENT_ID", nil),
+			},
+			"client_secret": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				RequiredWith: []string{"client_id"},
+				DefaultFunc:  schema.EnvDefaultFunc("KAFKAMANAGER_CLIENT_SECRET", nil),
+			},
+			"scopes": &schema.Schema{
+				Type:         schema.TypeList,
+				Optional:     true,
+				RequiredWith: []string{"client_id"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"ca_cert_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("KAFKAMANAGER_CA_CERT_FILE", nil),
+			},
+			"client_cert_file": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"client_key_file"},
+				DefaultFunc:  schema.EnvDefaultFunc("KAFKAMANAGER_CLIENT_CERT_FILE", nil),
+			},
+			"client_key_file": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"client_cert_file"},
+				DefaultFunc:  schema.EnvDefaultFunc("KAFKAMANAGER_CLIENT_KEY_FILE", nil),
+			},
+			"insecure_skip_verify": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"proxy_url": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithScheme([]string{"http", "https", "socks5"}),
+				DefaultFunc:  schema.EnvDefaultFunc("KAFKAMANAGER_PROXY_URL", nil),
+			},
+			"request_timeout": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(client.DefaultRequestTimeout / time.Second),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"requests_per_second": &schema.Schema{
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.FloatAtLeast(0),
+			},
+			"max_concurrent_requests": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"lookup_cache": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"lookup_cache_ttl": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(client.DefaultLookupCacheTTL / time.Second),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"batch_topic_reads": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"max_retries": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      client.DefaultMaxRetries,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retry_max_wait": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(client.DefaultRetryMaxWait / time.Second),
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"default_topic_config": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: defaultTopicConfigSchema(),
+				},
+			},
+			"default_tags": &schema.Schema{
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"kafkamanager_topic": resourceTopic(),
@@ -74,14 +195,82 @@ func Provider() *schema.Provider {
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	url := d.Get("url").(string)
 
+	retryPolicy := client.DefaultRetryPolicy()
+	retryPolicy.MaxRetries = d.Get("max_retries").(int)
+	retryPolicy.MaxWait = time.Duration(d.Get("retry_max_wait").(int)) * time.Second
+	if retryPolicy.MinWait > retryPolicy.MaxWait {
+		retryPolicy.MinWait = retryPolicy.MaxWait
+	}
+
+	httpClient, err := client.NewHTTPClient(client.TransportConfig{
+		CACertFile:         d.Get("ca_cert_file").(string),
+		ClientCertFile:     d.Get("client_cert_file").(string),
+		ClientKeyFile:      d.Get("client_key_file").(string),
+		InsecureSkipVerify: d.Get("insecure_skip_verify").(bool),
+		ProxyURL:           d.Get("proxy_url").(string),
+		Timeout:            time.Duration(d.Get("request_timeout").(int)) * time.Second,
+	})
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	var authenticator client.Authenticator
 	if accessToken, ok := d.GetOk("access_token"); ok {
-		return client.NewPublicClient(url, accessToken.(string)), nil
+		authenticator = &client.AccessTokenAuthenticator{
+			AccessToken: accessToken.(string),
+		}
 	} else if key, ok := d.GetOk("key"); ok {
-		oktaGroups := d.Get("okta_groups").(string)
-		supplier := d.Get("supplier").(string)
-		userID := d.Get("user_id").(string)
-		return client.NewPrivateClient(url, key.(string), oktaGroups, supplier, userID), nil
+		authenticator = &client.DataPlatformKeyAuthenticator{
+			Key:        key.(string),
+			OktaGroups: d.Get("okta_groups").(string),
+			Supplier:   d.Get("supplier").(string),
+			UserID:     d.Get("user_id").(string),
+		}
+	} else if clientID, ok := d.GetOk("client_id"); ok {
+		var scopes []string
+		for _, scope := range d.Get("scopes").([]interface{}) {
+			scopes = append(scopes, scope.(string))
+		}
+		oauth2Authenticator := client.NewOAuth2Authenticator(
+			d.Get("oauth_token_url").(string),
+			clientID.(string),
+			d.Get("client_secret").(string),
+			scopes,
+		)
+		oauth2Authenticator.HttpClient = httpClient
+		authenticator = oauth2Authenticator
 	} else {
-		return nil, diag.Errorf("provide either access token or Data-Platform Key, Okta groups, supplier code, and User ID")
+		return nil, diag.Errorf("provide either access token, OAuth2 client credentials, or Data-Platform Key, Okta groups, supplier code, and User ID")
 	}
+
+	c := client.NewClient(url, authenticator)
+	c.HttpClient = httpClient
+	if rps := d.Get("requests_per_second").(float64); rps > 0 {
+		c.RateLimiter = client.NewRateLimiter(rps, int(math.Ceil(rps)))
+	}
+	if maxConcurrentRequests := d.Get("max_concurrent_requests").(int); maxConcurrentRequests > 0 {
+		c.ConcurrencyLimiter = client.NewConcurrencyLimiter(maxConcurrentRequests)
+	}
+	c.RetryPolicy = retryPolicy
+
+	var meta client.Client = c
+	ttl := time.Duration(d.Get("lookup_cache_ttl").(int)) * time.Second
+	if d.Get("lookup_cache").(bool) {
+		meta = client.NewCachingClient(meta, ttl)
+	}
+	if d.Get("batch_topic_reads").(bool) {
+		meta = client.NewBatchingClient(meta)
+	}
+
+	return &providerMeta{
+		Client:        meta,
+		topicDefaults: unmarshalTopicDefaults(d),
+	}, nil
+}
+
+// providerMeta is the client handed to resources and data sources, together
+// with the defaults configured on the provider.
+type providerMeta struct {
+	client.Client
+	topicDefaults *topicDefaults
 }
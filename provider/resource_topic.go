@@ -3,11 +3,17 @@ package provider
 import (
 	"context"
 	"fmt"
+	"log"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"coxautoinc.com/data-platform/kafka-manager/client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func topicSchema() map[string]*schema.Schema {
@@ -16,39 +22,107 @@ func topicSchema() map[string]*schema.Schema {
 			Type: schema.TypeString,
 		},
 		"cluster_id": &schema.Schema{
-			Type: schema.TypeString,
+			Type:         schema.TypeString,
+			ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[0-9]+$`), "must be a numeric cluster ID"),
+		},
+		"cluster_name": &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		"environment_name": &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validation.StringIsNotEmpty,
 		},
 		"name": &schema.Schema{
 			Type: schema.TypeString,
+			ValidateFunc: validation.All(
+				validation.StringLenBetween(1, 249),
+				validation.StringMatch(regexp.MustCompile(`^[a-zA-Z0-9._-]+$`), "may only contain letters, digits, '.', '_' and '-'"),
+				validation.StringNotInSlice([]string{".", ".."}, false),
+			),
 		},
 		"partitions": &schema.Schema{
-			Type: schema.TypeInt,
+			Type:         schema.TypeInt,
+			ValidateFunc: validation.IntAtLeast(1),
 		},
 		"replication_factor": &schema.Schema{
-			Type: schema.TypeInt,
+			Type:         schema.TypeInt,
+			ValidateFunc: validation.IntAtLeast(1),
 		},
 		"max_message_bytes": &schema.Schema{
-			Type: schema.TypeInt,
+			Type:         schema.TypeInt,
+			ValidateFunc: validation.IntAtLeast(0),
 		},
 		"cleanup_policy": &schema.Schema{
-			Type: schema.TypeString,
+			Type:         schema.TypeString,
+			ValidateFunc: validation.StringInSlice([]string{"delete", "compact", "compact,delete"}, false),
 		},
 		"retention_ms": &schema.Schema{
-			Type: schema.TypeInt,
+			Type:         schema.TypeInt,
+			ValidateFunc: validation.IntAtLeast(-1),
 		},
 		"retention_bytes": &schema.Schema{
+			Type:         schema.TypeInt,
+			ValidateFunc: validation.IntAtLeast(-1),
+		},
+		"config": &schema.Schema{
+			Type: schema.TypeMap,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			ValidateFunc: validateTopicConfig,
+		},
+		"description": &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validation.StringLenBetween(0, 1024),
+		},
+		"owner": &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		"data_classification": &schema.Schema{
+			Type:         schema.TypeString,
+			ValidateFunc: validation.StringInSlice(dataClassifications, false),
+		},
+		"tags": &schema.Schema{
+			Type: schema.TypeMap,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"etag": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"version": &schema.Schema{
 			Type: schema.TypeInt,
 		},
 	}
 }
 
+var dataClassifications = []string{
+	client.DataClassificationPublic,
+	client.DataClassificationInternal,
+	client.DataClassificationConfidential,
+	client.DataClassificationRestricted,
+}
+
 func resourceTopic() *schema.Resource {
 	recordSchema := topicSchema()
 	recordSchema["id"].Computed = true
 	recordSchema["name"].Required = true
-	recordSchema["cluster_id"].Required = true
+	recordSchema["cluster_id"].Optional = true
+	recordSchema["cluster_id"].Computed = true
+	recordSchema["cluster_id"].ExactlyOneOf = []string{"cluster_id", "cluster_name"}
+	recordSchema["cluster_name"].Optional = true
+	recordSchema["cluster_name"].Computed = true
+	recordSchema["cluster_name"].ExactlyOneOf = []string{"cluster_id", "cluster_name"}
+	recordSchema["environment_name"].Optional = true
+	recordSchema["environment_name"].Computed = true
+	recordSchema["environment_name"].RequiredWith = []string{"cluster_name"}
 	recordSchema["partitions"].Optional = true
 	recordSchema["partitions"].Computed = true
+	// Settings left out of the configuration use the provider default, or
+	// else the cluster default. customizeTopicDefaults plans them.
 	recordSchema["max_message_bytes"].Optional = true
 	recordSchema["max_message_bytes"].Computed = true
 	recordSchema["cleanup_policy"].Optional = true
@@ -57,34 +131,212 @@ func resourceTopic() *schema.Resource {
 	recordSchema["retention_ms"].Computed = true
 	recordSchema["retention_bytes"].Optional = true
 	recordSchema["retention_bytes"].Computed = true
+	recordSchema["config"].Optional = true
+	recordSchema["config"].Computed = true
+	recordSchema["description"].Optional = true
+	recordSchema["owner"].Optional = true
+	recordSchema["data_classification"].Optional = true
+	recordSchema["tags"].Optional = true
+	recordSchema["tags"].Computed = true
 	recordSchema["replication_factor"].Optional = true
 	recordSchema["replication_factor"].Computed = true
+	recordSchema["replication_factor"].Description = "The number of replicas of each partition. Only Dedicated clusters change it in place. On other cluster types a change is rejected, unless allow_replication_factor_change_by_recreate is set to delete and recreate the topic, losing all data in it."
+	recordSchema["etag"].Computed = true
+	recordSchema["version"].Computed = true
+	recordSchema["deletion_protection"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Computed: true,
+	}
+	recordSchema["allow_partition_decrease_by_recreate"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+	recordSchema["allow_replication_factor_change_by_recreate"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
 
 	return &schema.Resource{
 		Schema:        recordSchema,
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceTopicStateTypeV0(),
+				Upgrade: resourceTopicStateUpgradeV0,
+				Version: 0,
+			},
+		},
 		CreateContext: resourceTopicCreate,
 		ReadContext:   resourceTopicRead,
 		UpdateContext: resourceTopicUpdate,
 		DeleteContext: resourceTopicDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceTopicImport,
+		},
+		CustomizeDiff: customdiff.All(
+			customizeTopicDefaults,
+			customizeTopicCluster,
+			customizeTopicDeletionProtection,
+			customizeTopicPartitions,
+			customizeTopicReplicationFactor,
+			customizeTopicClusterLimits,
+		),
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
 	}
 }
 
+// topicSettings are the topic settings that fall back to the cluster default
+// when they are not configured.
+var topicSettings = []string{
+	"max_message_bytes",
+	"cleanup_policy",
+	"retention_ms",
+	"retention_bytes",
+}
+
+// topicConfigKeys are the Kafka topic configs that may be set through the
+// config map, in addition to any config starting with confluent.
+var topicConfigKeys = []string{
+	"compression.type",
+	"delete.retention.ms",
+	"file.delete.delay.ms",
+	"flush.messages",
+	"flush.ms",
+	"index.interval.bytes",
+	"max.compaction.lag.ms",
+	"message.downconversion.enable",
+	"message.timestamp.difference.max.ms",
+	"message.timestamp.type",
+	"min.cleanable.dirty.ratio",
+	"min.compaction.lag.ms",
+	"min.insync.replicas",
+	"preallocate",
+	"segment.bytes",
+	"segment.index.bytes",
+	"segment.jitter.ms",
+	"segment.ms",
+	"unclean.leader.election.enable",
+}
+
+// topicConfigAttributes are Kafka topic configs that have an attribute of
+// their own and may not be set through the config map.
+var topicConfigAttributes = map[string]string{
+	"cleanup.policy":    "cleanup_policy",
+	"max.message.bytes": "max_message_bytes",
+	"retention.bytes":   "retention_bytes",
+	"retention.ms":      "retention_ms",
+}
+
+func validateTopicConfig(v interface{}, k string) (warnings []string, errs []error) {
+	for key := range v.(map[string]interface{}) {
+		if attribute, ok := topicConfigAttributes[key]; ok {
+			errs = append(errs, fmt.Errorf("%s: %q must be set with the %s attribute", k, key, attribute))
+			continue
+		}
+		if !isAllowedTopicConfig(key) {
+			errs = append(errs, fmt.Errorf("%s: %q is not a supported topic config", k, key))
+		}
+	}
+	return warnings, errs
+}
+
+func isAllowedTopicConfig(key string) bool {
+	if strings.HasPrefix(key, "confluent.") {
+		return true
+	}
+	for _, allowed := range topicConfigKeys {
+		if key == allowed {
+			return true
+		}
+	}
+	return false
+}
+
 func marshalTopic(t *client.Topic) (map[string]interface{}, error) {
+	config := t.Config
+	if config == nil {
+		config = &client.TopicConfig{}
+	}
+
 	result := map[string]interface{}{
-		"id":                 strconv.Itoa(t.ID),
-		"name":               t.Name,
-		"cluster_id":         strconv.Itoa(t.Cluster.ID),
-		"partitions":         t.Partitions,
-		"max_message_bytes":  t.Config.MaxMessageBytes,
-		"cleanup_policy":     t.Config.CleanupPolicy,
-		"retention_ms":       t.Config.RetentionMs,
-		"retention_bytes":    t.Config.RetentionBytes,
-		"replication_factor": t.Config.ReplicationFactor,
+		"id":                  strconv.Itoa(t.ID),
+		"name":                t.Name,
+		"cluster_id":          strconv.Itoa(t.Cluster.ID),
+		"cluster_name":        t.Cluster.Name,
+		"environment_name":    t.Cluster.Environment.Name,
+		"partitions":          t.Partitions,
+		"max_message_bytes":   intValue(config.MaxMessageBytes),
+		"cleanup_policy":      stringValue(config.CleanupPolicy),
+		"retention_ms":        intValue(config.RetentionMs),
+		"retention_bytes":     intValue(config.RetentionBytes),
+		"replication_factor":  intValue(config.ReplicationFactor),
+		"config":              stringMapValue(config.Settings),
+		"description":         stringValue(t.Description),
+		"owner":               stringValue(t.Owner),
+		"data_classification": stringValue(t.DataClassification),
+		"tags":                stringMapValue(t.Tags),
+		"etag":                t.ETag,
+		"version":             intValue(t.Version),
 	}
 
 	return result, nil
 }
 
+// intValue and stringValue turn an unset setting into nil, so that it is
+// stored as null rather than as a zero value.
+func intValue(v *int) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func stringValue(v *string) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func stringMapValue(m map[string]*string) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		if value != nil {
+			result[key] = *value
+		}
+	}
+	return result
+}
+
+// findTopicCluster looks up a cluster by name, within the named environment
+// if there is one.
+func findTopicCluster(ctx context.Context, c client.Client, clusterName string, environmentName string) (*client.Cluster, error) {
+	if environmentName == "" {
+		cluster, err := c.GetClusterByName(ctx, clusterName)
+		if err != nil {
+			return nil, fmt.Errorf("error reading cluster %s: %s", clusterName, err)
+		}
+		return cluster, nil
+	}
+
+	environment, err := c.GetEnvironmentByName(ctx, environmentName)
+	if err != nil {
+		return nil, fmt.Errorf("error reading environment %s: %s", environmentName, err)
+	}
+	cluster, err := c.GetClusterByNameAndEnvironmentID(ctx, clusterName, environment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cluster %s in environment %s: %s", clusterName, environmentName, err)
+	}
+	return cluster, nil
+}
+
 func unmarshalNewTopic(d *schema.ResourceData) (*client.NewTopic, error) {
 	clusterID, err := strconv.Atoi(d.Get("cluster_id").(string))
 	if err != nil {
@@ -92,46 +344,109 @@ func unmarshalNewTopic(d *schema.ResourceData) (*client.NewTopic, error) {
 	}
 
 	topic := &client.NewTopic{
-		Name:      d.Get("name").(string),
-		ClusterID: clusterID,
-		Config:    &client.TopicConfig{},
+		Name:               d.Get("name").(string),
+		ClusterID:          clusterID,
+		Config:             &client.TopicConfig{},
+		Description:        d.Get("description").(string),
+		Owner:              d.Get("owner").(string),
+		DataClassification: d.Get("data_classification").(string),
 	}
 
 	if v, ok := d.GetOk("partitions"); ok {
 		topic.Partitions = v.(int)
 	}
 	if v, ok := d.GetOk("replication_factor"); ok {
-		topic.Config.ReplicationFactor = v.(int)
+		topic.Config.ReplicationFactor = client.Int(v.(int))
+	}
+	if isConfigured(d, "max_message_bytes") {
+		topic.Config.MaxMessageBytes = client.Int(d.Get("max_message_bytes").(int))
+	}
+	if isConfigured(d, "cleanup_policy") {
+		topic.Config.CleanupPolicy = client.String(d.Get("cleanup_policy").(string))
 	}
-	if v, ok := d.GetOk("max_message_bytes"); ok {
-		topic.Config.MaxMessageBytes = v.(int)
+	if isConfigured(d, "retention_ms") {
+		topic.Config.RetentionMs = client.Int(d.Get("retention_ms").(int))
 	}
-	if v, ok := d.GetOk("cleanup_policy"); ok {
-		topic.Config.CleanupPolicy = v.(string)
+	if isConfigured(d, "retention_bytes") {
+		topic.Config.RetentionBytes = client.Int(d.Get("retention_bytes").(int))
 	}
-	if v, ok := d.GetOk("retention_ms"); ok {
-		topic.Config.RetentionMs = v.(int)
+	if v, ok := d.GetOk("config"); ok {
+		topic.Config.Settings = make(map[string]*string)
+		for key, value := range v.(map[string]interface{}) {
+			topic.Config.Settings[key] = client.String(value.(string))
+		}
 	}
-	if v, ok := d.GetOk("retention_bytes"); ok {
-		topic.Config.RetentionBytes = v.(int)
+	if v, ok := d.GetOk("tags"); ok {
+		topic.Tags = make(map[string]string)
+		for key, value := range v.(map[string]interface{}) {
+			topic.Tags[key] = value.(string)
+		}
 	}
 
 	return topic, nil
 }
 
+// diffStringMap returns the entries to send to move from o to n. Entries
+// that were removed are sent as nil, which removes them or, for settings,
+// restores the default.
+func diffStringMap(o, n map[string]interface{}) map[string]*string {
+	result := make(map[string]*string)
+	for key, value := range n {
+		if o[key] != value {
+			result[key] = client.String(value.(string))
+		}
+	}
+	for key := range o {
+		if _, ok := n[key]; !ok {
+			result[key] = nil
+		}
+	}
+	return result
+}
+
 func resourceTopicCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(client.Client)
+
+	// The cluster is resolved at plan time, unless its name was not known
+	// yet.
+	if d.Get("cluster_id").(string) == "" {
+		cluster, err := findTopicCluster(ctx, c, d.Get("cluster_name").(string), d.Get("environment_name").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("cluster_id", strconv.Itoa(cluster.ID))
+	}
+
+	// customizeTopicDeletionProtection leaves the default unknown when the
+	// cluster was not known at plan time.
+	if plan := d.GetRawPlan(); !plan.IsNull() && !plan.GetAttr("deletion_protection").IsKnown() {
+		clusterID, err := strconv.Atoi(d.Get("cluster_id").(string))
+		if err != nil {
+			return diag.Errorf("invalid cluster ID: %s", err)
+		}
+		cluster, err := c.GetCluster(ctx, clusterID)
+		if err != nil {
+			return diag.Errorf("error reading cluster %d: %s", clusterID, err)
+		}
+		d.Set("deletion_protection", isProductionEnvironment(cluster.Environment.Name))
+	}
+
 	newTopic, err := unmarshalNewTopic(d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	topic, err := c.CreateTopic(newTopic)
+	topic, err := c.CreateTopic(ctx, newTopic)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	d.SetId(strconv.Itoa(topic.ID))
+
+	if err := waitForTopicReady(ctx, c, topic.ID, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return resourceTopicRead(ctx, d, meta)
 }
 
@@ -142,8 +457,13 @@ func resourceTopicRead(ctx context.Context, d *schema.ResourceData, meta interfa
 		return diag.Errorf("invalid topic ID: %s", err)
 	}
 
-	rawTopic, err := c.GetTopic(id)
+	rawTopic, err := readTopic(ctx, c, d, id)
 	if err != nil {
+		if client.IsNotFound(err) {
+			log.Printf("[WARN] topic %s not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
 		return diag.Errorf("error reading topic: %s", err)
 	}
 
@@ -151,12 +471,51 @@ func resourceTopicRead(ctx context.Context, d *schema.ResourceData, meta interfa
 	if err != nil {
 		return diag.Errorf("error reading topic: %s", err)
 	}
+	// Only track the settings that are managed here, the others follow the
+	// cluster default.
+	for _, key := range topicSettings {
+		if !isConfigured(d, key) {
+			delete(topic, key)
+		}
+	}
+	managed := d.Get("config").(map[string]interface{})
+	for key := range topic["config"].(map[string]interface{}) {
+		if _, ok := managed[key]; !ok {
+			delete(topic["config"].(map[string]interface{}), key)
+		}
+	}
 	d.SetId(topic["id"].(string))
 	setResourceDataFromMap(d, topic)
 
 	return nil
 }
 
+// readTopic reads the topic, from a listing of its cluster when reads are
+// batched. Listed topics have no ETag. The one in state still applies while
+// the topic is at the version it was read at, otherwise the topic is read
+// on its own to get the current one.
+func readTopic(ctx context.Context, c client.Client, d *schema.ResourceData, id int) (*client.Topic, error) {
+	clusterID, err := strconv.Atoi(d.Get("cluster_id").(string))
+	if err != nil {
+		return c.GetTopic(ctx, id)
+	}
+	topic, err := c.GetTopicInCluster(ctx, clusterID, id)
+	if err != nil || topic.ETag != "" {
+		return topic, err
+	}
+
+	etag := d.Get("etag").(string)
+	if etag == "" {
+		// Kafka Manager did not send one before either.
+		return topic, nil
+	}
+	if version, ok := stateInt(d, "version"); ok && topic.Version != nil && version == *topic.Version {
+		topic.ETag = etag
+		return topic, nil
+	}
+	return c.GetTopic(ctx, id)
+}
+
 func resourceTopicUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(client.Client)
 
@@ -165,29 +524,86 @@ func resourceTopicUpdate(ctx context.Context, d *schema.ResourceData, meta inter
 		return diag.Errorf("invalid topic ID: %s", err)
 	}
 
+	if !d.HasChangesExcept("allow_partition_decrease_by_recreate", "allow_replication_factor_change_by_recreate", "deletion_protection", "cluster_name", "environment_name") {
+		return resourceTopicRead(ctx, d, meta)
+	}
+
 	topic := &client.Topic{
 		ID:     id,
 		Config: &client.TopicConfig{},
+		ETag:   d.Get("etag").(string),
+	}
+
+	// Decreases never get here, customizeTopicPartitions either rejects
+	// them or replaces the topic.
+	if d.HasChange("partitions") {
+		topic.Partitions = d.Get("partitions").(int)
+	}
+	// Likewise customizeTopicReplicationFactor replaces the topic unless the
+	// cluster can reassign replicas.
+	if d.HasChange("replication_factor") {
+		topic.Config.ReplicationFactor = client.Int(d.Get("replication_factor").(int))
+	}
+
+	if hasChange(d, "max_message_bytes") {
+		if isConfigured(d, "max_message_bytes") {
+			topic.Config.MaxMessageBytes = client.Int(d.Get("max_message_bytes").(int))
+		} else {
+			topic.Config.Reset = append(topic.Config.Reset, client.TopicConfigMaxMessageBytes)
+		}
+	}
+	if hasChange(d, "cleanup_policy") {
+		if isConfigured(d, "cleanup_policy") {
+			topic.Config.CleanupPolicy = client.String(d.Get("cleanup_policy").(string))
+		} else {
+			topic.Config.Reset = append(topic.Config.Reset, client.TopicConfigCleanupPolicy)
+		}
+	}
+	if hasChange(d, "retention_ms") {
+		if isConfigured(d, "retention_ms") {
+			topic.Config.RetentionMs = client.Int(d.Get("retention_ms").(int))
+		} else {
+			topic.Config.Reset = append(topic.Config.Reset, client.TopicConfigRetentionMs)
+		}
+	}
+	if hasChange(d, "retention_bytes") {
+		if isConfigured(d, "retention_bytes") {
+			topic.Config.RetentionBytes = client.Int(d.Get("retention_bytes").(int))
+		} else {
+			topic.Config.Reset = append(topic.Config.Reset, client.TopicConfigRetentionBytes)
+		}
 	}
 
-	if d.HasChange("max_message_bytes") {
-		topic.Config.MaxMessageBytes = d.Get("max_message_bytes").(int)
+	if d.HasChange("config") {
+		o, n := d.GetChange("config")
+		topic.Config.Settings = diffStringMap(o.(map[string]interface{}), n.(map[string]interface{}))
+	}
+	if d.HasChange("description") {
+		topic.Description = client.String(d.Get("description").(string))
 	}
-	if d.HasChange("cleanup_policy") {
-		topic.Config.CleanupPolicy = d.Get("cleanup_policy").(string)
+	if d.HasChange("owner") {
+		topic.Owner = client.String(d.Get("owner").(string))
 	}
-	if d.HasChange("retention_ms") {
-		topic.Config.RetentionMs = d.Get("retention_ms").(int)
+	if d.HasChange("data_classification") {
+		topic.DataClassification = client.String(d.Get("data_classification").(string))
 	}
-	if d.HasChange("retention_bytes") {
-		topic.Config.RetentionBytes = d.Get("retention_bytes").(int)
+	if d.HasChange("tags") {
+		o, n := d.GetChange("tags")
+		topic.Tags = diffStringMap(o.(map[string]interface{}), n.(map[string]interface{}))
 	}
 
-	err = c.UpdateTopic(topic)
+	err = c.UpdateTopic(ctx, topic)
 	if err != nil {
+		if isConcurrentModification(err) {
+			return concurrentModificationDiag(d.Id())
+		}
 		return diag.Errorf("failed to update topic: %s", err)
 	}
 
+	if err := waitForTopicReady(ctx, c, id, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return resourceTopicRead(ctx, d, meta)
 }
 
@@ -198,10 +614,300 @@ func resourceTopicDelete(ctx context.Context, d *schema.ResourceData, meta inter
 		return diag.Errorf("invalid topic ID: %s", err)
 	}
 
-	err = c.DeleteTopic(id)
+	if d.Get("deletion_protection").(bool) {
+		return diag.Diagnostics{
+			{
+				Severity: diag.Error,
+				Summary:  "Topic is protected from deletion",
+				Detail:   fmt.Sprintf("Topic %s (%s) has deletion_protection enabled. Set deletion_protection to false and apply before deleting it.", d.Id(), d.Get("name").(string)),
+			},
+		}
+	}
+
+	err = c.DeleteTopic(ctx, id, d.Get("etag").(string))
 	if err != nil {
+		if client.IsNotFound(err) {
+			return nil
+		}
+		if isConcurrentModification(err) {
+			return concurrentModificationDiag(d.Id())
+		}
 		return diag.Errorf("failed to delete topic: %s", err)
 	}
 
+	if err := waitForTopicDeleted(ctx, c, id, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
+
+// resourceTopicImport accepts the Kafka Manager ID of the topic, or
+// <cluster name>/<topic name>, or <cluster id>/<topic name>.
+func resourceTopicImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	c := meta.(client.Client)
+
+	if err := d.Set("allow_partition_decrease_by_recreate", false); err != nil {
+		return nil, err
+	}
+	if err := d.Set("allow_replication_factor_change_by_recreate", false); err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		if id, err = findTopicID(ctx, c, d.Id()); err != nil {
+			return nil, err
+		}
+	}
+
+	topic, err := c.GetTopic(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error reading topic %s: %s", d.Id(), err)
+	}
+	values, err := marshalTopic(topic)
+	if err != nil {
+		return nil, fmt.Errorf("error reading topic %s: %s", d.Id(), err)
+	}
+
+	// Read only keeps the settings and config keys that are in state, so
+	// store everything the topic has. The first plan then shows the actual
+	// differences to the configuration.
+	d.SetId(values["id"].(string))
+	for _, key := range append([]string{"cluster_id", "config"}, topicSettings...) {
+		if err := d.Set(key, values[key]); err != nil {
+			return nil, err
+		}
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// findTopicID resolves an import ID of the form <cluster name>/<topic name>
+// or <cluster id>/<topic name>.
+func findTopicID(ctx context.Context, c client.Client, importID string) (int, error) {
+	// Topic names cannot contain a slash, cluster names might.
+	i := strings.LastIndex(importID, "/")
+	if i <= 0 || i == len(importID)-1 {
+		return 0, fmt.Errorf("invalid import ID %q, expected <topic id>, <cluster name>/<topic name> or <cluster id>/<topic name>", importID)
+	}
+	clusterRef, name := importID[:i], importID[i+1:]
+
+	clusterID, err := strconv.Atoi(clusterRef)
+	if err != nil {
+		cluster, err := c.GetClusterByName(ctx, clusterRef)
+		if err != nil {
+			return 0, fmt.Errorf("error reading cluster %s: %s", clusterRef, err)
+		}
+		clusterID = cluster.ID
+	}
+
+	topic, err := c.GetTopicByNameAndClusterID(ctx, name, clusterID)
+	if err != nil {
+		return 0, fmt.Errorf("error reading topic %s: %s", importID, err)
+	}
+	return topic.ID, nil
+}
+
+// customizeTopicPartitions rejects a partition decrease, which Kafka does not
+// support, unless the topic may be recreated to apply it.
+func customizeTopicPartitions(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("partitions") || !d.NewValueKnown("partitions") {
+		return nil
+	}
+
+	o, n := d.GetChange("partitions")
+	if n.(int) >= o.(int) {
+		return nil
+	}
+
+	if d.Get("allow_partition_decrease_by_recreate").(bool) {
+		return forceTopicReplacement(d, "partitions")
+	}
+	return fmt.Errorf("cannot decrease partitions of topic %s from %d to %d: Kafka does not support removing partitions. Set allow_partition_decrease_by_recreate to delete and recreate the topic, losing its data", d.Id(), o.(int), n.(int))
+}
+
+// customizeTopicReplicationFactor plans a replication factor change as a
+// reassignment on clusters that support it. Everywhere else the change is
+// rejected, unless the topic may be recreated to apply it.
+func customizeTopicReplicationFactor(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("replication_factor") || !d.NewValueKnown("replication_factor") {
+		return nil
+	}
+
+	if d.NewValueKnown("cluster_id") {
+		clusterID, err := strconv.Atoi(d.Get("cluster_id").(string))
+		if err != nil {
+			return fmt.Errorf("invalid cluster ID: %s", err)
+		}
+		cluster, err := meta.(client.Client).GetCluster(ctx, clusterID)
+		if err != nil {
+			return fmt.Errorf("error reading cluster %d: %s", clusterID, err)
+		}
+		if cluster.CanReassignReplicas() {
+			return nil
+		}
+		if !d.Get("allow_replication_factor_change_by_recreate").(bool) {
+			o, n := d.GetChange("replication_factor")
+			return fmt.Errorf("cannot change the replication factor of topic %s from %d to %d: %s cluster %s does not support reassigning replicas. Set allow_replication_factor_change_by_recreate to delete and recreate the topic, losing its data", d.Id(), o.(int), n.(int), cluster.ClusterType, cluster.Name)
+		}
+	}
+
+	// A topic whose cluster is not known yet is moving to another cluster,
+	// and is replaced anyway.
+	return forceTopicReplacement(d, "replication_factor")
+}
+
+// customizeTopicCluster resolves cluster_name to a cluster ID, and plans the
+// replacement of the topic when it moves to another cluster.
+func customizeTopicCluster(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	_, configured := d.GetOk("cluster_name")
+	known := d.NewValueKnown("cluster_name")
+	if config := d.GetRawConfig(); !config.IsNull() {
+		name := config.GetAttr("cluster_name")
+		configured, known = !name.IsNull(), name.IsKnown()
+	}
+
+	switch {
+	case !configured:
+	case !known:
+		if err := d.SetNewComputed("cluster_id"); err != nil {
+			return err
+		}
+	case d.Id() == "" || d.HasChange("cluster_name") || d.HasChange("environment_name"):
+		clusterName := d.Get("cluster_name").(string)
+		if clusterName == "" {
+			break
+		}
+		cluster, err := findTopicCluster(ctx, meta.(client.Client), clusterName, d.Get("environment_name").(string))
+		if err != nil {
+			return err
+		}
+		if err := d.SetNew("cluster_id", strconv.Itoa(cluster.ID)); err != nil {
+			return err
+		}
+	}
+
+	if d.Id() != "" && d.HasChange("cluster_id") {
+		return forceTopicReplacement(d, "cluster_id")
+	}
+	return nil
+}
+
+// customizeTopicClusterLimits rejects partitions and max_message_bytes the
+// cluster does not allow, so that the plan fails rather than the apply. The
+// partition limit applies to the whole cluster, so the partitions of the
+// other topics in it count as well. They are counted from the listing that
+// batched reads share, so a plan lists each cluster once. Topics created in
+// the same run are not in the cluster yet, and are only caught at apply
+// time.
+func customizeTopicClusterLimits(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChange("cluster_id") && !d.HasChange("partitions") && !d.HasChange("max_message_bytes") {
+		return nil
+	}
+	if !d.NewValueKnown("cluster_id") {
+		return nil
+	}
+
+	c := meta.(client.Client)
+	clusterID, err := strconv.Atoi(d.Get("cluster_id").(string))
+	if err != nil {
+		return fmt.Errorf("invalid cluster ID: %s", err)
+	}
+	cluster, err := c.GetCluster(ctx, clusterID)
+	if err != nil {
+		return fmt.Errorf("error reading cluster %d: %s", clusterID, err)
+	}
+	limits := cluster.KnownLimits()
+
+	name := d.Get("name").(string)
+	if maxMessageBytes := d.Get("max_message_bytes").(int); limits.MaxMessageBytes > 0 && d.NewValueKnown("max_message_bytes") && maxMessageBytes > limits.MaxMessageBytes {
+		return fmt.Errorf("topic %s cannot have max_message_bytes %d: %s cluster %s allows at most %d bytes. Lower max_message_bytes, or use a cluster that allows larger messages", name, maxMessageBytes, cluster.ClusterType, cluster.Name, limits.MaxMessageBytes)
+	}
+
+	o, n := d.GetChange("partitions")
+	grows := d.Id() == "" || d.HasChange("cluster_id") || n.(int) > o.(int)
+	if limits.MaxPartitions == 0 || !grows || !d.NewValueKnown("partitions") {
+		return nil
+	}
+
+	topics, err := c.ListTopicsInCluster(ctx, clusterID)
+	if err != nil {
+		return fmt.Errorf("error listing topics of cluster %d: %s", clusterID, err)
+	}
+	used := 0
+	for _, t := range topics {
+		// A topic that is replaced is deleted first, and its replacement
+		// has no ID yet, so the topic is recognized by name as well.
+		if strconv.Itoa(t.ID) == d.Id() || t.Name == name {
+			continue
+		}
+		used += t.Partitions
+	}
+	if partitions := n.(int); used+partitions > limits.MaxPartitions {
+		return fmt.Errorf("topic %s cannot have %d partitions: %s cluster %s allows at most %d partitions across all topics, and other topics already use %d. Lower partitions, delete unused topics, or use a Dedicated cluster", name, partitions, cluster.ClusterType, cluster.Name, limits.MaxPartitions, used)
+	}
+	return nil
+}
+
+var (
+	productionEnvironmentName = regexp.MustCompile(`(?i)(^|[^a-z])prod(uction)?([^a-z]|$)`)
+	// Names such as non-prod, pre-prod or not_prod say the opposite.
+	nonProductionEnvironmentName = regexp.MustCompile(`(?i)(^|[^a-z])(non|pre|not)[^a-z]?prod(uction)?([^a-z]|$)`)
+)
+
+func isProductionEnvironment(name string) bool {
+	return productionEnvironmentName.MatchString(nonProductionEnvironmentName.ReplaceAllString(name, " "))
+}
+
+// customizeTopicDeletionProtection protects topics in production
+// environments from deletion, unless deletion_protection is configured.
+func customizeTopicDeletionProtection(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if isRawAttributeSet(d.GetRawConfig(), "deletion_protection") {
+		return nil
+	}
+	// Keep the value of existing topics, but do pick a default for topics
+	// managed since before deletion_protection existed.
+	if state := d.GetRawState(); d.Id() != "" && (state.IsNull() || isRawAttributeSet(state, "deletion_protection")) {
+		return nil
+	}
+	if !d.NewValueKnown("cluster_id") {
+		// resourceTopicCreate decides once the cluster is known.
+		return d.SetNewComputed("deletion_protection")
+	}
+
+	clusterID, err := strconv.Atoi(d.Get("cluster_id").(string))
+	if err != nil {
+		return fmt.Errorf("invalid cluster ID: %s", err)
+	}
+	cluster, err := meta.(client.Client).GetCluster(ctx, clusterID)
+	if err != nil {
+		return fmt.Errorf("error reading cluster %d: %s", clusterID, err)
+	}
+	return d.SetNew("deletion_protection", isProductionEnvironment(cluster.Environment.Name))
+}
+
+// forceTopicReplacement plans the replacement of the topic because of a
+// change to key, unless the topic is protected from deletion. The delete
+// runs against the prior state, so turning the protection off in the same
+// change does not help.
+func forceTopicReplacement(d *schema.ResourceDiff, key string) error {
+	if o, _ := d.GetChange("deletion_protection"); o.(bool) {
+		return fmt.Errorf("changing %s requires replacing topic %s, which deletes all data in it, but the topic is protected from deletion. Set deletion_protection to false and apply that change on its own first", key, d.Id())
+	}
+	return d.ForceNew(key)
+}
+
+func isConcurrentModification(err error) bool {
+	return client.IsConflict(err) || client.IsPreconditionFailed(err)
+}
+
+func concurrentModificationDiag(id string) diag.Diagnostics {
+	return diag.Diagnostics{
+		{
+			Severity: diag.Error,
+			Summary:  "Topic was modified outside this run",
+			Detail:   fmt.Sprintf("Topic %s changed in Kafka Manager after Terraform last read it, so the planned change was not applied. Run terraform plan again to review the current state.", id),
+		},
+	}
+}
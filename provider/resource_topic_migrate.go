@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceTopicV0 is the kafkamanager_topic schema before unmanaged topic
+// settings were left out of state.
+func resourceTopicV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"cluster_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"partitions": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"replication_factor": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"max_message_bytes": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"cleanup_policy": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"retention_ms": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"retention_bytes": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceTopicStateTypeV0() cty.Type {
+	return resourceTopicV0().CoreConfigSchema().ImpliedType()
+}
+
+// resourceTopicStateUpgradeV0 drops the topic settings from state. Version
+// 0 stored every setting Kafka Manager reported, also those the
+// configuration left to the cluster default. Kept in state, those would be
+// planned as removed from the configuration and reset on the next apply.
+// Settings that are configured show as added once, and are sent unchanged.
+func resourceTopicStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	for _, key := range topicSettings {
+		delete(rawState, key)
+	}
+	return rawState, nil
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"coxautoinc.com/data-platform/kafka-manager/client"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceTopicStateUpgradeV0(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 10, "name": "cluster1", "clusterType": "Dedicated", "environment": {"name": "dev"}}`))
+	}))
+	defer server.Close()
+
+	p := Provider()
+	p.SetMeta(&providerMeta{Client: client.NewClient(server.URL, nil)})
+
+	// Version 0 stored the cluster defaults of settings that are not
+	// configured.
+	res, err := schema.NewGRPCProviderServer(p).UpgradeResourceState(context.Background(), &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: "kafkamanager_topic",
+		Version:  0,
+		RawState: &tfprotov5.RawState{
+			JSON: []byte(`{"id": "42", "name": "orders", "cluster_id": "10", "partitions": 6, "replication_factor": 3, "max_message_bytes": 1048588, "cleanup_policy": "delete", "retention_ms": 86400000, "retention_bytes": -1}`),
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, diag := range res.Diagnostics {
+		t.Fatalf("unexpected diagnostic: %s: %s", diag.Summary, diag.Detail)
+	}
+
+	ty := p.ResourcesMap["kafkamanager_topic"].CoreConfigSchema().ImpliedType()
+	upgraded, err := msgpack.Unmarshal(res.UpgradedState.MsgPack, ty)
+	if err != nil {
+		t.Fatalf("unable to decode upgraded state: %s", err)
+	}
+	prior := make(map[string]cty.Value)
+	for name, v := range upgraded.AsValueMap() {
+		if !v.IsNull() {
+			prior[name] = v
+		}
+	}
+	for _, key := range topicSettings {
+		if _, ok := prior[key]; ok {
+			t.Errorf("expected %s to be dropped from state", key)
+		}
+	}
+
+	planned := planTopic(t, p, prior, map[string]cty.Value{
+		"name":       cty.StringVal("orders"),
+		"cluster_id": cty.StringVal("10"),
+		"partitions": cty.NumberIntVal(6),
+	})
+	for _, key := range topicSettings {
+		if got := planned[key]; !got.IsKnown() || !got.IsNull() {
+			t.Errorf("expected %s to stay unmanaged, got %#v", key, got)
+		}
+	}
+}
@@ -1,15 +1,20 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"coxautoinc.com/data-platform/kafka-manager/client"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -33,18 +38,24 @@ func testSweepTopics(region string) error {
 		return err
 	}
 
-	topics, err := c.GetTopics()
-	if err != nil {
+	// Collect matches before deleting anything, otherwise deletions shift
+	// the pages that are still to be read.
+	var topics []client.Topic
+	it := c.IterateTopics(context.Background())
+	for it.Next() {
+		if t := it.Topic(); strings.HasPrefix(t.Name, topicNamePrefix) {
+			topics = append(topics, t)
+		}
+	}
+	if err := it.Err(); err != nil {
 		return err
 	}
 
 	for _, t := range topics {
-		if strings.HasPrefix(t.Name, topicNamePrefix) {
-			log.Printf("Deleting Topic %s", t.Name)
+		log.Printf("Deleting Topic %s", t.Name)
 
-			if err := c.DeleteTopic(t.ID); err != nil {
-				return err
-			}
+		if err := c.DeleteTopic(context.Background(), t.ID, ""); err != nil {
+			return err
 		}
 	}
 
@@ -100,11 +111,15 @@ func TestMarshalTopic(t *testing.T) {
 	}
 	topicConfig := &client.TopicConfig{
 		ID:                1,
-		ReplicationFactor: 3,
-		MaxMessageBytes:   16,
-		CleanupPolicy:     "delete",
-		RetentionMs:       30,
-		RetentionBytes:    16,
+		ReplicationFactor: client.Int(3),
+		MaxMessageBytes:   client.Int(16),
+		CleanupPolicy:     client.String("delete"),
+		RetentionMs:       client.Int(30),
+		RetentionBytes:    client.Int(16),
+		Settings: map[string]*string{
+			"min.insync.replicas": client.String("2"),
+			"segment.ms":          nil,
+		},
 	}
 	topic := &client.Topic{
 		ID:         10,
@@ -112,17 +127,34 @@ func TestMarshalTopic(t *testing.T) {
 		Partitions: 6,
 		Cluster:    cluster,
 		Config:     topicConfig,
+		Owner:      client.String("data-platform"),
+		Tags: map[string]*string{
+			"domain": client.String("sales"),
+		},
 	}
 	goodTestData := map[string]interface{}{
 		"id":                 "10",
 		"name":               "test-topic",
 		"cluster_id":         "10",
+		"cluster_name":       "cluster1",
+		"environment_name":   "new-env",
 		"partitions":         6,
 		"max_message_bytes":  16,
 		"cleanup_policy":     "delete",
 		"retention_ms":       30,
 		"retention_bytes":    16,
 		"replication_factor": 3,
+		"config": map[string]interface{}{
+			"min.insync.replicas": "2",
+		},
+		"description":         nil,
+		"owner":               "data-platform",
+		"data_classification": nil,
+		"tags": map[string]interface{}{
+			"domain": "sales",
+		},
+		"etag":    "",
+		"version": nil,
 	}
 	result, err := marshalTopic(topic)
 
@@ -135,6 +167,586 @@ func TestMarshalTopic(t *testing.T) {
 	}
 }
 
+func TestTopicSchemaValidation(t *testing.T) {
+	tests := []struct {
+		key   string
+		value interface{}
+		valid bool
+	}{
+		{"name", "orders.v1_created-events", true},
+		{"name", "orders/v1", false},
+		{"name", "..", false},
+		{"name", strings.Repeat("a", 249), true},
+		{"name", strings.Repeat("a", 250), false},
+		{"cluster_id", "10", true},
+		{"cluster_id", "cluster1", false},
+		{"partitions", 1, true},
+		{"partitions", -1, false},
+		{"replication_factor", 0, false},
+		{"cleanup_policy", "compact,delete", true},
+		{"cleanup_policy", "compacted", false},
+		{"retention_ms", -1, true},
+		{"retention_ms", -2, false},
+		{"retention_bytes", -1, true},
+		{"max_message_bytes", -1, false},
+	}
+
+	topicSchema := resourceTopic().Schema
+	for _, tt := range tests {
+		_, errs := topicSchema[tt.key].ValidateFunc(tt.value, tt.key)
+		if valid := len(errs) == 0; valid != tt.valid {
+			t.Errorf("%s = %v: expected valid to be %t, got errors %v", tt.key, tt.value, tt.valid, errs)
+		}
+	}
+}
+
+func TestDiffStringMap(t *testing.T) {
+	o := map[string]interface{}{
+		"min.insync.replicas": "2",
+		"segment.ms":          "3600000",
+		"compression.type":    "lz4",
+	}
+	n := map[string]interface{}{
+		"min.insync.replicas": "2",
+		"segment.ms":          "7200000",
+		"preallocate":         "true",
+	}
+
+	expected := map[string]*string{
+		"segment.ms":       client.String("7200000"),
+		"preallocate":      client.String("true"),
+		"compression.type": nil,
+	}
+	result := diffStringMap(o, n)
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Error matching, expected: %#v and got %#v", expected, result)
+	}
+}
+
+func TestValidateTopicConfig(t *testing.T) {
+	valid := map[string]interface{}{
+		"min.insync.replicas":               "2",
+		"confluent.value.schema.validation": "true",
+	}
+	if _, errs := validateTopicConfig(valid, "config"); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	invalid := map[string]interface{}{
+		"retention.ms": "1000",
+		"not.a.config": "1",
+	}
+	if _, errs := validateTopicConfig(invalid, "config"); len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+}
+
+func TestCustomizeTopicPartitions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 10, "name": "cluster1", "environment": {"name": "dev"}}`))
+	}))
+	defer server.Close()
+	c := client.NewClient(server.URL, nil)
+
+	state := &terraform.InstanceState{
+		ID: "10",
+		Attributes: map[string]string{
+			"id":         "10",
+			"name":       "test-topic",
+			"cluster_id": "10",
+			"partitions": "6",
+		},
+	}
+	config := func(partitions int, recreate bool) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":                                 "test-topic",
+			"cluster_id":                           "10",
+			"partitions":                           partitions,
+			"allow_partition_decrease_by_recreate": recreate,
+		})
+	}
+
+	diff, err := resourceTopic().Diff(context.Background(), state, config(12, false), c)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff.RequiresNew() {
+		t.Fatalf("expected an increase to be applied in place")
+	}
+
+	_, err = resourceTopic().Diff(context.Background(), state, config(3, false), c)
+	if err == nil {
+		t.Fatalf("expected a decrease to be rejected")
+	}
+
+	diff, err = resourceTopic().Diff(context.Background(), state, config(3, true), c)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !diff.RequiresNew() {
+		t.Fatalf("expected a decrease to replace the topic")
+	}
+}
+
+func TestCustomizeTopicReplicationFactor(t *testing.T) {
+	clusterType := "Standard"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id": 10, "name": "cluster1", "clusterType": %q}`, clusterType)
+	}))
+	defer server.Close()
+	c := client.NewClient(server.URL, nil)
+
+	state := &terraform.InstanceState{
+		ID: "10",
+		Attributes: map[string]string{
+			"id":                 "10",
+			"name":               "test-topic",
+			"cluster_id":         "10",
+			"partitions":         "6",
+			"replication_factor": "3",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":               "test-topic",
+		"cluster_id":         "10",
+		"partitions":         6,
+		"replication_factor": 4,
+	})
+
+	_, err := resourceTopic().Diff(context.Background(), state, config, c)
+	if err == nil || !strings.Contains(err.Error(), "allow_replication_factor_change_by_recreate") {
+		t.Fatalf("expected a %s cluster to reject the change, got %v", clusterType, err)
+	}
+
+	recreate := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":               "test-topic",
+		"cluster_id":         "10",
+		"partitions":         6,
+		"replication_factor": 4,
+		"allow_replication_factor_change_by_recreate": true,
+	})
+	diff, err := resourceTopic().Diff(context.Background(), state, recreate, c)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !diff.RequiresNew() {
+		t.Fatalf("expected a %s cluster to replace the topic", clusterType)
+	}
+
+	clusterType = client.ClusterTypeDedicated
+	diff, err = resourceTopic().Diff(context.Background(), state, config, c)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if diff.RequiresNew() {
+		t.Fatalf("expected a %s cluster to reassign replicas in place", clusterType)
+	}
+}
+
+func TestCustomizeTopicClusterLimits(t *testing.T) {
+	var listings int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/topics":
+			atomic.AddInt32(&listings, 1)
+			w.Write([]byte(`{"items": [{"id": 1, "name": "payments", "partitionsCount": 4000}, {"id": 42, "name": "test-topic", "partitionsCount": 90}]}`))
+		default:
+			w.Write([]byte(`{"id": 10, "name": "cluster1", "clusterType": "Standard", "environment": {"name": "dev"}}`))
+		}
+	}))
+	defer server.Close()
+	c := client.NewClient(server.URL, nil)
+
+	existing := &terraform.InstanceState{
+		ID: "42",
+		Attributes: map[string]string{
+			"id":                 "42",
+			"name":               "test-topic",
+			"cluster_id":         "10",
+			"partitions":         "90",
+			"replication_factor": "3",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		state    *terraform.InstanceState
+		config   map[string]interface{}
+		err      string
+		listings int32
+	}{
+		{
+			name:     "within limits",
+			config:   map[string]interface{}{"partitions": 96, "max_message_bytes": 8388608},
+			listings: 1,
+		},
+		{
+			name:     "cluster full",
+			config:   map[string]interface{}{"partitions": 100},
+			err:      "Standard cluster cluster1 allows at most 4096 partitions across all topics, and other topics already use 4000",
+			listings: 1,
+		},
+		{
+			name:     "increase",
+			state:    existing,
+			config:   map[string]interface{}{"partitions": 100},
+			err:      "other topics already use 4000",
+			listings: 1,
+		},
+		{
+			name:   "unchanged partitions",
+			state:  existing,
+			config: map[string]interface{}{"partitions": 90, "max_message_bytes": 1048576},
+		},
+		{
+			name:   "message size",
+			config: map[string]interface{}{"partitions": 6, "max_message_bytes": 10485760},
+			err:    "Standard cluster cluster1 allows at most 8388608 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&listings, 0)
+			raw := map[string]interface{}{
+				"name":               "test-topic",
+				"cluster_id":         "10",
+				"replication_factor": 3,
+			}
+			for k, v := range tt.config {
+				raw[k] = v
+			}
+
+			_, err := resourceTopic().Diff(context.Background(), tt.state, terraform.NewResourceConfigRaw(raw), c)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("unexpected error: %s", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Fatalf("expected error containing %q, got %v", tt.err, err)
+			}
+			if listings != tt.listings {
+				t.Fatalf("expected %d listings, got %d", tt.listings, listings)
+			}
+		})
+	}
+}
+
+func TestCustomizeTopicCluster(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.URL.Path == "/environments" && q.Get("name") == "dev":
+			w.Write([]byte(`{"items": [{"id": 7, "name": "dev"}]}`))
+		case r.URL.Path == "/clusters" && q.Get("name") == "shared" && q.Get("environment.id") == "7":
+			w.Write([]byte(`{"items": [{"id": 12, "name": "shared", "environment": {"id": 7, "name": "dev"}}]}`))
+		case r.URL.Path == "/clusters" && q.Get("name") == "shared":
+			w.Write([]byte(`{"items": [{"id": 11, "name": "shared", "environment": {"id": 6, "name": "qa"}}]}`))
+		case strings.HasPrefix(r.URL.Path, "/clusters/"):
+			w.Write([]byte(`{"id": 10, "environment": {"name": "dev"}}`))
+		default:
+			w.Write([]byte(`{"items": []}`))
+		}
+	}))
+	defer server.Close()
+	c := client.NewClient(server.URL, nil)
+
+	tests := []struct {
+		config    map[string]interface{}
+		clusterID string
+	}{
+		{map[string]interface{}{"cluster_name": "shared"}, "11"},
+		{map[string]interface{}{"cluster_name": "shared", "environment_name": "dev"}, "12"},
+	}
+	for _, tt := range tests {
+		tt.config["name"] = "test-topic"
+		diff, err := resourceTopic().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tt.config), c)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %s", tt.config, err)
+		}
+		if got := diff.Attributes["cluster_id"].New; got != tt.clusterID {
+			t.Fatalf("%v: expected cluster ID %s, got %s", tt.config, tt.clusterID, got)
+		}
+	}
+
+	state := &terraform.InstanceState{
+		ID: "10",
+		Attributes: map[string]string{
+			"id":                  "10",
+			"name":                "test-topic",
+			"cluster_id":          "11",
+			"cluster_name":        "shared",
+			"environment_name":    "qa",
+			"deletion_protection": "false",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":             "test-topic",
+		"cluster_name":     "shared",
+		"environment_name": "dev",
+	})
+	diff, err := resourceTopic().Diff(context.Background(), state, config, c)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !diff.RequiresNew() {
+		t.Fatalf("expected moving the topic to another cluster to replace it")
+	}
+}
+
+func TestCustomizeTopicDeletionProtection(t *testing.T) {
+	var deletes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			atomic.AddInt32(&deletes, 1)
+		}
+		switch r.URL.Path {
+		case "/clusters/10":
+			w.Write([]byte(`{"id": 10, "name": "cluster1", "environment": {"name": "prod"}}`))
+		case "/clusters/11":
+			w.Write([]byte(`{"id": 11, "name": "cluster2", "environment": {"name": "nonprod-dev"}}`))
+		}
+	}))
+	defer server.Close()
+	c := client.NewClient(server.URL, nil)
+
+	for clusterID, expected := range map[string]string{"10": "true", "11": "false"} {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":       "test-topic",
+			"cluster_id": clusterID,
+		})
+		diff, err := resourceTopic().Diff(context.Background(), nil, config, c)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got := diff.Attributes["deletion_protection"].New; got != expected {
+			t.Fatalf("cluster %s: expected deletion_protection %s, got %s", clusterID, expected, got)
+		}
+	}
+
+	state := &terraform.InstanceState{
+		ID: "10",
+		Attributes: map[string]string{
+			"id":                  "10",
+			"name":                "test-topic",
+			"cluster_id":          "10",
+			"partitions":          "6",
+			"deletion_protection": "true",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":                                 "test-topic",
+		"cluster_id":                           "10",
+		"partitions":                           3,
+		"allow_partition_decrease_by_recreate": true,
+	})
+	if _, err := resourceTopic().Diff(context.Background(), state, config, c); err == nil {
+		t.Fatalf("expected the replacement of a protected topic to be rejected")
+	}
+
+	// The delete of the replacement would still see the protection.
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":                                 "test-topic",
+		"cluster_id":                           "10",
+		"partitions":                           3,
+		"allow_partition_decrease_by_recreate": true,
+		"deletion_protection":                  false,
+	})
+	if _, err := resourceTopic().Diff(context.Background(), state, config, c); err == nil {
+		t.Fatalf("expected the replacement to be rejected while lifting the protection")
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceTopic().Schema, map[string]interface{}{
+		"name":                "test-topic",
+		"cluster_id":          "10",
+		"deletion_protection": true,
+	})
+	d.SetId("10")
+	if diags := resourceTopicDelete(context.Background(), d, c); !diags.HasError() {
+		t.Fatalf("expected the deletion of a protected topic to be rejected")
+	}
+	if deletes != 0 {
+		t.Fatalf("expected no delete request, got %d", deletes)
+	}
+}
+
+func TestResourceTopicCreate_DeletionProtectionOfUnknownCluster(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/clusters":
+			w.Write([]byte(`{"items": [{"id": 11, "name": "cluster2", "environment": {"name": "dev"}}]}`))
+		case r.URL.Path == "/clusters/11":
+			w.Write([]byte(`{"id": 11, "name": "cluster2", "environment": {"name": "dev"}}`))
+		case r.URL.Path == "/topics" && r.Method == http.MethodPost:
+			w.Write([]byte(`{"id": 42, "name": "orders", "cluster": {"id": 11}}`))
+		case r.URL.Path == "/topics/42":
+			w.Write([]byte(`{"id": 42, "name": "orders", "cluster": {"id": 11, "name": "cluster2", "environment": {"name": "dev"}}, "partitionsCount": 6, "status": "READY"}`))
+		}
+	}))
+	defer server.Close()
+
+	p := Provider()
+	p.SetMeta(&providerMeta{Client: client.NewClient(server.URL, nil)})
+
+	// The cluster is created in the same run.
+	config := map[string]cty.Value{
+		"name":         cty.StringVal("orders"),
+		"cluster_name": cty.UnknownVal(cty.String),
+		"partitions":   cty.NumberIntVal(6),
+	}
+	planned := planTopic(t, p, nil, config)
+	if got := planned["deletion_protection"]; got.IsKnown() {
+		t.Fatalf("expected deletion_protection to be known after apply, got %#v", got)
+	}
+
+	config["cluster_name"] = cty.StringVal("cluster2")
+	planned["cluster_name"] = cty.StringVal("cluster2")
+	applied := applyTopic(t, p, planned, config)
+	if got := applied["deletion_protection"]; !got.RawEquals(cty.False) {
+		t.Fatalf("expected a dev cluster to leave the topic unprotected, got %#v", got)
+	}
+}
+
+func TestIsProductionEnvironment(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"prod":              true,
+		"PROD":              true,
+		"data-production":   true,
+		"us-prod-2":         true,
+		"prod-and-pre-prod": true,
+		"dev":               false,
+		"nonprod-dev":       false,
+		"non-prod":          false,
+		"pre-prod":          false,
+		"not_prod":          false,
+		"Pre Production":    false,
+		"product":           false,
+	} {
+		if got := isProductionEnvironment(name); got != expected {
+			t.Errorf("%s: expected %t, got %t", name, expected, got)
+		}
+	}
+}
+
+func TestResourceTopicRead_ETag(t *testing.T) {
+	version := 3
+	var gets int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		topic := fmt.Sprintf(`{"id": 42, "name": "orders", "cluster": {"id": 10}, "partitionsCount": 6, "version": %d}`, version)
+		switch r.URL.Path {
+		case "/topics":
+			fmt.Fprintf(w, `{"items": [%s]}`, topic)
+		case "/topics/42":
+			atomic.AddInt32(&gets, 1)
+			if version < 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, version))
+			w.Write([]byte(topic))
+		}
+	}))
+	defer server.Close()
+
+	read := func(c client.Client, state map[string]interface{}) *schema.ResourceData {
+		d := schema.TestResourceDataRaw(t, resourceTopic().Schema, state)
+		d.SetId("42")
+		if diags := resourceTopicRead(context.Background(), d, c); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+		return d
+	}
+	batching := func() client.Client {
+		return client.NewBatchingClient(client.NewClient(server.URL, nil))
+	}
+
+	// Without batching, the topic is read once, with its ETag.
+	d := read(client.NewClient(server.URL, nil), map[string]interface{}{"name": "orders", "cluster_id": "10"})
+	if got := d.Get("etag").(string); got != `"etag-3"` || gets != 1 {
+		t.Fatalf("expected the ETag of a single read, got %q from %d reads", got, gets)
+	}
+
+	// At the same version, the ETag read before is kept.
+	state := map[string]interface{}{"name": "orders", "cluster_id": "10", "etag": `"etag-3"`, "version": 3}
+	d = read(batching(), state)
+	if got := d.Get("etag").(string); got != `"etag-3"` || gets != 1 {
+		t.Fatalf("expected the ETag to be kept, got %q from %d reads", got, gets)
+	}
+
+	// Once the topic changed, the ETag is read again.
+	version = 4
+	d = read(batching(), state)
+	if got := d.Get("etag").(string); got != `"etag-4"` || gets != 2 {
+		t.Fatalf("expected a new ETag, got %q from %d reads", got, gets)
+	}
+
+	// A topic deleted since the listing is removed from state.
+	version = -1
+	d = read(batching(), state)
+	if d.Id() != "" {
+		t.Fatalf("expected the topic to be removed from state")
+	}
+}
+
+func TestResourceTopicImport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/clusters" && r.URL.Query().Get("name") == "cluster1":
+			w.Write([]byte(`{"items": [{"id": 10, "name": "cluster1"}]}`))
+		case r.URL.Path == "/topics" && r.URL.Query().Get("name") == "orders" && r.URL.Query().Get("cluster.id") == "10":
+			w.Write([]byte(`{"items": [{"id": 42, "name": "orders"}]}`))
+		case r.URL.Path == "/topics/42":
+			w.Write([]byte(`{"id": 42, "name": "orders", "cluster": {"id": 10}, "config": {"retentionMs": 604800000, "settings": {"min.insync.replicas": "2"}}}`))
+		default:
+			w.Write([]byte(`{"items": []}`))
+		}
+	}))
+	defer server.Close()
+	c := client.NewClient(server.URL, nil)
+
+	tests := []struct {
+		importID  string
+		id        string
+		clusterID string
+	}{
+		{importID: "42", id: "42", clusterID: "10"},
+		{importID: "cluster1/orders", id: "42", clusterID: "10"},
+		{importID: "10/orders", id: "42", clusterID: "10"},
+	}
+	for _, tt := range tests {
+		d := schema.TestResourceDataRaw(t, resourceTopic().Schema, nil)
+		d.SetId(tt.importID)
+
+		result, err := resourceTopicImport(context.Background(), d, c)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.importID, err)
+		}
+		if id := result[0].Id(); id != tt.id {
+			t.Fatalf("%s: expected ID %s, got %s", tt.importID, tt.id, id)
+		}
+		if clusterID := result[0].Get("cluster_id").(string); clusterID != tt.clusterID {
+			t.Fatalf("%s: expected cluster ID %q, got %q", tt.importID, tt.clusterID, clusterID)
+		}
+		if retentionMs := result[0].Get("retention_ms").(int); retentionMs != 604800000 {
+			t.Fatalf("%s: expected retention_ms to be imported, got %d", tt.importID, retentionMs)
+		}
+		if config := result[0].Get("config").(map[string]interface{}); config["min.insync.replicas"] != "2" {
+			t.Fatalf("%s: expected config to be imported, got %#v", tt.importID, config)
+		}
+		if _, ok := result[0].GetOk("cleanup_policy"); ok {
+			t.Fatalf("%s: expected cleanup_policy to stay unset", tt.importID)
+		}
+	}
+
+	for _, importID := range []string{"orders", "cluster1/", "missing/orders", "10/missing"} {
+		d := schema.TestResourceDataRaw(t, resourceTopic().Schema, nil)
+		d.SetId(importID)
+
+		if _, err := resourceTopicImport(context.Background(), d, c); err == nil {
+			t.Fatalf("%s: expected an error", importID)
+		}
+	}
+}
+
 func TestAccTopicCreation(t *testing.T) {
 	// TODO: Replace hardcoded values with something.
 	awsEnvironment := "cinp"
@@ -190,14 +802,14 @@ func testAccCheckKafkaManagerTopicExists(rn string, topic *client.Topic) resourc
 			return fmt.Errorf("no topic ID is set")
 		}
 
-		c := testAccProvider.Meta().(*client.PrivateClient)
+		c := testAccProvider.Meta().(client.Client)
 
 		topic_id, err := strconv.Atoi(rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("Invalid topic ID: %s", err)
 		}
 
-		got, err := c.GetTopic(topic_id)
+		got, err := c.GetTopic(context.Background(), topic_id)
 		if err != nil {
 			return err
 		}
@@ -212,7 +824,7 @@ func testAccCheckKafkaManagerTopicExists(rn string, topic *client.Topic) resourc
 }
 
 func testAccCheckKafkaManagerTopicDestroy(s *terraform.State) error {
-	c := testAccProvider.Meta().(*client.PrivateClient)
+	c := testAccProvider.Meta().(client.Client)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "kafkamanager_topic" {
@@ -224,7 +836,7 @@ func testAccCheckKafkaManagerTopicDestroy(s *terraform.State) error {
 			return fmt.Errorf("Invalid topic ID: %s", err)
 		}
 
-		_, err = c.GetTopic(topic_id)
+		_, err = c.GetTopic(context.Background(), topic_id)
 		if err == nil {
 			return fmt.Errorf("Topic still exists")
 		}
@@ -13,7 +13,7 @@ func TestMain(m *testing.M) {
 	resource.TestMain(m)
 }
 
-func sharedPrivateClientForRegion(region string) (*client.PrivateClient, error) {
+func sharedPrivateClientForRegion(region string) (*client.APIClient, error) {
 	// NOTE: region is not used at this moment and is only needed to conform to Terraform testing API.
 
 	url := os.Getenv("KAFKAMANAGER_URL")
@@ -39,7 +39,12 @@ func sharedPrivateClientForRegion(region string) (*client.PrivateClient, error)
 	}
 
 	if key != "" {
-		return client.NewPrivateClient(url, key, oktaGroups, supplier, userID), nil
+		return client.NewClient(url, &client.DataPlatformKeyAuthenticator{
+			Key:        key,
+			OktaGroups: oktaGroups,
+			Supplier:   supplier,
+			UserID:     userID,
+		}), nil
 	} else {
 		return nil, fmt.Errorf("provide KAFKAMANAGER_KEY")
 	}
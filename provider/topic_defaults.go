@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// topicDefaults are the topic settings and tags configured on the provider
+// with default_topic_config and default_tags.
+type topicDefaults struct {
+	// values holds defaults for top-level topic attributes, keyed by
+	// attribute name.
+	values map[string]interface{}
+	config map[string]interface{}
+	tags   map[string]interface{}
+}
+
+// topicCreationDefaults only apply to new topics. Changing them on an
+// existing topic could replace it.
+var topicCreationDefaults = []string{
+	"partitions",
+	"replication_factor",
+}
+
+func defaultTopicConfigSchema() map[string]*schema.Schema {
+	recordSchema := topicSchema()
+
+	result := make(map[string]*schema.Schema)
+	for _, key := range append(append([]string{"config"}, topicCreationDefaults...), topicSettings...) {
+		result[key] = recordSchema[key]
+		result[key].Optional = true
+	}
+	return result
+}
+
+func unmarshalTopicDefaults(d *schema.ResourceData) *topicDefaults {
+	defaults := &topicDefaults{
+		values: make(map[string]interface{}),
+		config: make(map[string]interface{}),
+		tags:   d.Get("default_tags").(map[string]interface{}),
+	}
+
+	if v, ok := d.GetOk("default_topic_config"); ok && v.([]interface{})[0] != nil {
+		config := v.([]interface{})[0].(map[string]interface{})
+		// Zero cannot be told apart from an unset value here, and is
+		// not a sensible default for any of these settings.
+		for _, key := range append(append([]string{}, topicCreationDefaults...), topicSettings...) {
+			if value, ok := config[key]; ok && value != 0 && value != "" {
+				defaults.values[key] = value
+			}
+		}
+		defaults.config = config["config"].(map[string]interface{})
+	}
+
+	return defaults
+}
+
+func topicDefaultsFromMeta(meta interface{}) *topicDefaults {
+	if m, ok := meta.(*providerMeta); ok && m.topicDefaults != nil {
+		return m.topicDefaults
+	}
+	return &topicDefaults{}
+}
+
+// customizeTopicDefaults plans the provider defaults for the settings,
+// config and tags a topic does not set itself. A setting that is neither
+// configured nor defaulted any more is planned to be reset to the cluster
+// default, which shows as known after apply.
+func customizeTopicDefaults(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	defaults := topicDefaultsFromMeta(meta)
+
+	config := d.GetRawConfig()
+	configured := func(key string) bool {
+		if !config.IsNull() {
+			return !config.GetAttr(key).IsNull()
+		}
+		// The raw config is missing when the diff of a replacement is
+		// planned. That diff is planned as for a new topic, where only
+		// configured attributes are known.
+		return d.NewValueKnown(key)
+	}
+	configuredMap := func(key string) (map[string]interface{}, bool) {
+		if !config.IsNull() {
+			v := config.GetAttr(key)
+			return rawStringMap(v), v.IsWhollyKnown()
+		}
+		if !d.NewValueKnown(key) {
+			return nil, true
+		}
+		return d.Get(key).(map[string]interface{}), true
+	}
+
+	if d.Id() == "" {
+		for _, key := range topicCreationDefaults {
+			if value, ok := defaults.values[key]; ok && !configured(key) {
+				if err := d.SetNew(key, value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, key := range topicSettings {
+		if configured(key) {
+			continue
+		}
+		if value, ok := defaults.values[key]; ok {
+			if err := d.SetNew(key, value); err != nil {
+				return err
+			}
+		} else if d.Id() != "" && isRawAttributeSet(d.GetRawState(), key) {
+			// The cluster default is only known once it has been reset.
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	for key, defaultMap := range map[string]map[string]interface{}{"config": defaults.config, "tags": defaults.tags} {
+		overrides, known := configuredMap(key)
+		if !known {
+			continue
+		}
+		if err := d.SetNew(key, mergeStringMaps(defaultMap, overrides)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mergeStringMaps returns the entries of both maps, preferring those of
+// overrides.
+func mergeStringMaps(defaults, overrides map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(defaults)+len(overrides))
+	for key, value := range defaults {
+		result[key] = value
+	}
+	for key, value := range overrides {
+		result[key] = value
+	}
+	return result
+}
+
+func rawStringMap(v cty.Value) map[string]interface{} {
+	result := make(map[string]interface{})
+	if v.IsNull() {
+		return result
+	}
+	for it := v.ElementIterator(); it.Next(); {
+		key, value := it.Element()
+		if !value.IsNull() {
+			result[key.AsString()] = value.AsString()
+		}
+	}
+	return result
+}
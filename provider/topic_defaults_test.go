@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"coxautoinc.com/data-platform/kafka-manager/client"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// planTopic plans a kafkamanager_topic the way Terraform does, with the
+// raw config and state available to CustomizeDiff.
+func planTopic(t *testing.T, p *schema.Provider, prior map[string]cty.Value, config map[string]cty.Value) map[string]cty.Value {
+	block := p.ResourcesMap["kafkamanager_topic"].CoreConfigSchema()
+	ty := block.ImpliedType()
+
+	object := func(values map[string]cty.Value) cty.Value {
+		if values == nil {
+			return cty.NullVal(ty)
+		}
+		attributes := make(map[string]cty.Value)
+		for name, attributeType := range ty.AttributeTypes() {
+			if v, ok := values[name]; ok {
+				attributes[name] = v
+			} else {
+				attributes[name] = cty.NullVal(attributeType)
+			}
+		}
+		return cty.ObjectVal(attributes)
+	}
+	encode := func(v cty.Value) *tfprotov5.DynamicValue {
+		b, err := msgpack.Marshal(v, ty)
+		if err != nil {
+			t.Fatalf("unable to encode value: %s", err)
+		}
+		return &tfprotov5.DynamicValue{MsgPack: b}
+	}
+
+	// Terraform proposes the configured values, and keeps the prior value
+	// of computed attributes that are not configured.
+	proposed := make(map[string]cty.Value)
+	for name, v := range config {
+		proposed[name] = v
+	}
+	for name, v := range prior {
+		if _, ok := config[name]; !ok && block.Attributes[name] != nil && block.Attributes[name].Computed {
+			proposed[name] = v
+		}
+	}
+	if prior == nil {
+		for name, attribute := range block.Attributes {
+			if _, ok := config[name]; !ok && attribute.Computed {
+				proposed[name] = cty.UnknownVal(attribute.Type)
+			}
+		}
+	}
+
+	res, err := schema.NewGRPCProviderServer(p).PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         "kafkamanager_topic",
+		PriorState:       encode(object(prior)),
+		ProposedNewState: encode(object(proposed)),
+		Config:           encode(object(config)),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, diag := range res.Diagnostics {
+		t.Fatalf("unexpected diagnostic: %s: %s", diag.Summary, diag.Detail)
+	}
+
+	planned, err := msgpack.Unmarshal(res.PlannedState.MsgPack, ty)
+	if err != nil {
+		t.Fatalf("unable to decode planned state: %s", err)
+	}
+	return planned.AsValueMap()
+}
+
+// applyTopic creates a kafkamanager_topic from a plan of planTopic.
+func applyTopic(t *testing.T, p *schema.Provider, planned map[string]cty.Value, config map[string]cty.Value) map[string]cty.Value {
+	block := p.ResourcesMap["kafkamanager_topic"].CoreConfigSchema()
+	ty := block.ImpliedType()
+
+	object := func(values map[string]cty.Value) cty.Value {
+		attributes := make(map[string]cty.Value)
+		for name, attributeType := range ty.AttributeTypes() {
+			if v, ok := values[name]; ok {
+				attributes[name] = v
+			} else {
+				attributes[name] = cty.NullVal(attributeType)
+			}
+		}
+		return cty.ObjectVal(attributes)
+	}
+	encode := func(v cty.Value) *tfprotov5.DynamicValue {
+		b, err := msgpack.Marshal(v, ty)
+		if err != nil {
+			t.Fatalf("unable to encode value: %s", err)
+		}
+		return &tfprotov5.DynamicValue{MsgPack: b}
+	}
+
+	res, err := schema.NewGRPCProviderServer(p).ApplyResourceChange(context.Background(), &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:     "kafkamanager_topic",
+		PriorState:   encode(cty.NullVal(ty)),
+		PlannedState: encode(object(planned)),
+		Config:       encode(object(config)),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, diag := range res.Diagnostics {
+		t.Fatalf("unexpected diagnostic: %s: %s", diag.Summary, diag.Detail)
+	}
+
+	applied, err := msgpack.Unmarshal(res.NewState.MsgPack, ty)
+	if err != nil {
+		t.Fatalf("unable to decode new state: %s", err)
+	}
+	return applied.AsValueMap()
+}
+
+func TestCustomizeTopicDefaults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 10, "name": "cluster1", "clusterType": "Dedicated", "environment": {"name": "dev"}}`))
+	}))
+	defer server.Close()
+
+	p := Provider()
+	meta := &providerMeta{
+		Client: client.NewClient(server.URL, nil),
+		topicDefaults: &topicDefaults{
+			values: map[string]interface{}{
+				"replication_factor": 3,
+				"retention_ms":       604800000,
+			},
+			config: map[string]interface{}{"min.insync.replicas": "2", "segment.ms": "3600000"},
+			tags:   map[string]interface{}{"team": "data-platform"},
+		},
+	}
+	p.SetMeta(meta)
+
+	config := map[string]cty.Value{
+		"name":       cty.StringVal("orders"),
+		"cluster_id": cty.StringVal("10"),
+		"partitions": cty.NumberIntVal(6),
+		"config":     cty.MapVal(map[string]cty.Value{"segment.ms": cty.StringVal("7200000")}),
+		"tags":       cty.MapVal(map[string]cty.Value{"domain": cty.StringVal("sales")}),
+	}
+
+	planned := planTopic(t, p, nil, config)
+
+	expected := map[string]cty.Value{
+		"replication_factor": cty.NumberIntVal(3),
+		"retention_ms":       cty.NumberIntVal(604800000),
+		"config": cty.MapVal(map[string]cty.Value{
+			"min.insync.replicas": cty.StringVal("2"),
+			"segment.ms":          cty.StringVal("7200000"),
+		}),
+		"tags": cty.MapVal(map[string]cty.Value{
+			"domain": cty.StringVal("sales"),
+			"team":   cty.StringVal("data-platform"),
+		}),
+	}
+	for name, want := range expected {
+		if got := planned[name]; !got.RawEquals(want) {
+			t.Errorf("create: expected %s to be planned as %#v, got %#v", name, want, got)
+		}
+	}
+
+	// Once created, the inherited values are in the state and planning the
+	// same configuration again changes nothing.
+	prior := make(map[string]cty.Value)
+	for name, v := range planned {
+		if v.IsKnown() {
+			prior[name] = v
+		}
+	}
+	prior["id"] = cty.StringVal("42")
+	prior["etag"] = cty.StringVal(`"1"`)
+	prior["cluster_name"] = cty.StringVal("cluster1")
+	prior["environment_name"] = cty.StringVal("dev")
+	prior["deletion_protection"] = cty.False
+
+	planned = planTopic(t, p, prior, config)
+	for name, want := range prior {
+		if got := planned[name]; !got.RawEquals(want) {
+			t.Errorf("update: expected %s to stay %#v, got %#v", name, want, got)
+		}
+	}
+
+	// Without the provider default, retention_ms goes back to the cluster
+	// default.
+	meta.topicDefaults.values = map[string]interface{}{"replication_factor": 3}
+
+	planned = planTopic(t, p, prior, config)
+	if got := planned["retention_ms"]; got.IsKnown() {
+		t.Errorf("expected retention_ms to be reset, got %#v", got)
+	}
+}
+
+func TestUnmarshalTopicDefaults(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"default_topic_config": []interface{}{
+			map[string]interface{}{
+				"replication_factor": 3,
+				"retention_bytes":    -1,
+				"config": map[string]interface{}{
+					"min.insync.replicas": "2",
+				},
+			},
+		},
+		"default_tags": map[string]interface{}{
+			"team": "data-platform",
+		},
+	})
+
+	defaults := unmarshalTopicDefaults(d)
+
+	expected := &topicDefaults{
+		values: map[string]interface{}{
+			"replication_factor": 3,
+			"retention_bytes":    -1,
+		},
+		config: map[string]interface{}{"min.insync.replicas": "2"},
+		tags:   map[string]interface{}{"team": "data-platform"},
+	}
+	if !reflect.DeepEqual(expected, defaults) {
+		t.Fatalf("Error matching, expected: %#v and got %#v", expected, defaults)
+	}
+}
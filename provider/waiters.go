@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"coxautoinc.com/data-platform/kafka-manager/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const (
+	topicStatusDeleted = "DELETED"
+
+	topicWaiterDelay      = 2 * time.Second
+	topicWaiterMinTimeout = 2 * time.Second
+)
+
+// topicStatusRefreshFunc reports the provisioning status of a topic. Topics
+// whose status is not reported are treated as ready, and a missing topic is
+// reported as deleted.
+func topicStatusRefreshFunc(ctx context.Context, c client.Client, id int) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		topic, err := c.GetTopic(ctx, id)
+		if err != nil {
+			if client.IsNotFound(err) {
+				return &client.Topic{ID: id}, topicStatusDeleted, nil
+			}
+			return nil, "", err
+		}
+
+		switch topic.Status {
+		case "":
+			return topic, client.TopicStatusReady, nil
+		case client.TopicStatusFailed:
+			return topic, topic.Status, fmt.Errorf("provisioning of topic %d failed", id)
+		default:
+			return topic, topic.Status, nil
+		}
+	}
+}
+
+func waitForTopicReady(ctx context.Context, c client.Client, id int, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{client.TopicStatusProvisioning, topicStatusDeleted},
+		Target:     []string{client.TopicStatusReady},
+		Refresh:    topicStatusRefreshFunc(ctx, c, id),
+		Timeout:    timeout,
+		Delay:      topicWaiterDelay,
+		MinTimeout: topicWaiterMinTimeout,
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for topic %d to become ready: %s", id, err)
+	}
+	return nil
+}
+
+func waitForTopicDeleted(ctx context.Context, c client.Client, id int, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{client.TopicStatusReady, client.TopicStatusProvisioning, client.TopicStatusDeleting},
+		Target:     []string{topicStatusDeleted},
+		Refresh:    topicStatusRefreshFunc(ctx, c, id),
+		Timeout:    timeout,
+		Delay:      topicWaiterDelay,
+		MinTimeout: topicWaiterMinTimeout,
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("error waiting for topic %d to be deleted: %s", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"coxautoinc.com/data-platform/kafka-manager/client"
+)
+
+func TestTopicStatusRefreshFunc(t *testing.T) {
+	responses := map[string]struct {
+		status int
+		body   string
+	}{
+		"/topics/1": {http.StatusOK, `{"id": 1, "status": "PROVISIONING"}`},
+		"/topics/2": {http.StatusOK, `{"id": 2}`},
+		"/topics/3": {http.StatusOK, `{"id": 3, "status": "FAILED"}`},
+		"/topics/4": {http.StatusNotFound, `{"code": "topics.does_not_exist"}`},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := responses[r.URL.Path]
+		w.WriteHeader(res.status)
+		w.Write([]byte(res.body))
+	}))
+	defer server.Close()
+
+	c := client.NewClient(server.URL, nil)
+
+	cases := []struct {
+		id      int
+		state   string
+		wantErr bool
+	}{
+		{1, client.TopicStatusProvisioning, false},
+		{2, client.TopicStatusReady, false},
+		{3, client.TopicStatusFailed, true},
+		{4, topicStatusDeleted, false},
+	}
+	for _, tc := range cases {
+		_, state, err := topicStatusRefreshFunc(context.Background(), c, tc.id)()
+		if state != tc.state {
+			t.Errorf("topic %d: expected state %q, got %q", tc.id, tc.state, state)
+		}
+		if (err != nil) != tc.wantErr {
+			t.Errorf("topic %d: unexpected error %v", tc.id, err)
+		}
+	}
+}